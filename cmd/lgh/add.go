@@ -25,24 +25,26 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/JoeGlenn1213/lgh/internal/config"
-	"github.com/JoeGlenn1213/lgh/internal/event"
 	"github.com/JoeGlenn1213/lgh/internal/git"
 	"github.com/JoeGlenn1213/lgh/internal/ignore"
 	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/repos"
 	"github.com/JoeGlenn1213/lgh/internal/server"
 	"github.com/JoeGlenn1213/lgh/pkg/ui"
 )
 
 var (
-	repoName    string
-	noRemote    bool
-	autoPush    bool
-	pushBranch  string
-	addNoIgnore bool
+	repoName     string
+	noRemote     bool
+	autoPush     bool
+	pushBranch   string
+	addNoIgnore  bool
+	addNamespace string
 )
 
 var addCmd = &cobra.Command{
@@ -67,6 +69,7 @@ Examples:
   lgh add                      # Add current directory
   lgh add ./my-project         # Add specific path
   lgh add . --name my-app      # Add with custom name
+  lgh add . --namespace team   # Add as team/<dir> (http://host/team/<dir>.git)
   lgh add . --no-remote        # Don't add remote to source repo
   lgh add . --push             # Auto-commit & push (One-step setup)
   lgh add . --push-branch main # Auto-push specific branch`,
@@ -76,6 +79,7 @@ Examples:
 
 func init() {
 	addCmd.Flags().StringVarP(&repoName, "name", "n", "", "Custom name for the repository")
+	addCmd.Flags().StringVar(&addNamespace, "namespace", "", "Namespace for the repository, e.g. team or team/project")
 	addCmd.Flags().BoolVar(&noRemote, "no-remote", false, "Don't add 'lgh' remote to the source repository")
 	addCmd.Flags().BoolVar(&autoPush, "push", false, "Automatically push current branch to LGH remote")
 	addCmd.Flags().StringVar(&pushBranch, "push-branch", "", "Specify branch to push (defaults to current HEAD)")
@@ -151,10 +155,9 @@ func runAdd(_ *cobra.Command, args []string) error {
 		name = filepath.Base(absPath)
 	}
 
-	// Ensure name ends with .git for bare repo
-	bareRepoName := name
-	if filepath.Ext(bareRepoName) != ".git" {
-		bareRepoName = name + ".git"
+	name = registry.JoinName(addNamespace, strings.TrimSuffix(name, ".git"))
+	if err := registry.ValidateName(name); err != nil {
+		return err
 	}
 
 	ui.Title("Adding Repository: %s", name)
@@ -167,24 +170,24 @@ func runAdd(_ *cobra.Command, args []string) error {
 
 	// Create bare repository path
 	cfg := config.Get()
-	barePath := filepath.Join(cfg.ReposDir, bareRepoName)
+	barePath := registry.BarePathFor(cfg.ReposDir, name)
 
 	// Check if bare repo already exists
 	if _, err := os.Stat(barePath); err == nil {
 		return fmt.Errorf("bare repository already exists at %s", barePath)
 	}
 
-	// Create bare repository
+	// Build the remote URL
+	// http(s)://localhost:PORT/lgh/repo.git, or /team/repo.git with a namespace
+	remoteURL := cfg.BaseURL(cfg.BindAddress) + registry.URLPath(name)
+
+	// Create and register the bare repository
 	ui.Info("Creating bare repository...")
-	if err := git.InitBareRepo(barePath); err != nil {
+	if _, err := repos.Create(reg, cfg, repos.CreateOptions{Name: name, SourcePath: absPath, URL: remoteURL}); err != nil {
 		return err
 	}
 	ui.Success("Created %s", barePath)
-
-	// Build the remote URL
-	// v1.0.6: Use strict virtual owner 'lgh' for compatibility
-	// http://localhost:PORT/lgh/repo.git
-	remoteURL := fmt.Sprintf("http://%s:%d/lgh/%s", cfg.BindAddress, cfg.Port, bareRepoName)
+	ui.Success("Registered in mappings.yaml")
 
 	// Add remote to source repository
 	if !noRemote {
@@ -197,25 +200,10 @@ func runAdd(_ *cobra.Command, args []string) error {
 		}
 	}
 
-	// Register mapping
-	ui.Info("Registering repository...")
-	if err := reg.Add(name, absPath, barePath); err != nil {
-		// Cleanup: remove bare repo if registration fails
-		_ = os.RemoveAll(barePath)
-		return fmt.Errorf("failed to register repository: %w", err)
-	}
-	ui.Success("Registered in mappings.yaml")
-
 	// Print success and next steps
 	fmt.Println()
 	ui.Success("Repository '%s' added successfully!", name)
 
-	// Publish Event
-	event.Publish(event.RepoAdded, name, map[string]interface{}{
-		"source": absPath,
-		"bare":   barePath,
-		"url":    remoteURL,
-	})
 	fmt.Println()
 
 	// Check if server is running
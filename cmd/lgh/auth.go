@@ -23,16 +23,21 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 
 	"github.com/JoeGlenn1213/lgh/internal/config"
 	"github.com/JoeGlenn1213/lgh/internal/server"
+	"github.com/JoeGlenn1213/lgh/internal/tokens"
+	"github.com/JoeGlenn1213/lgh/internal/users"
 	"github.com/JoeGlenn1213/lgh/pkg/ui"
 )
 
@@ -91,11 +96,36 @@ var authStatusCmd = &cobra.Command{
 	RunE:  runAuthStatus,
 }
 
+var (
+	lockoutsClear bool
+	lockoutsKey   string
+)
+
+var authLockoutsCmd = &cobra.Command{
+	Use:   "lockouts",
+	Short: "Show or clear login lockouts",
+	Long: `Show client IPs and usernames with failed login attempts.
+
+After 5 failed attempts a client is locked out for 30 seconds, doubling
+with every further failure up to 15 minutes. Locked-out clients get
+'429 Too Many Requests' with a Retry-After header.
+
+Examples:
+  lgh auth lockouts                        # List failed attempts
+  lgh auth lockouts --clear                # Clear all lockouts
+  lgh auth lockouts --clear --key user:bob # Clear one lockout`,
+	RunE: runAuthLockouts,
+}
+
 func init() {
+	authLockoutsCmd.Flags().BoolVar(&lockoutsClear, "clear", false, "Clear lockouts")
+	authLockoutsCmd.Flags().StringVar(&lockoutsKey, "key", "", "Only clear this entry (e.g. ip:10.0.0.5 or user:bob)")
+
 	authCmd.AddCommand(authSetupCmd)
 	authCmd.AddCommand(authHashCmd)
 	authCmd.AddCommand(authDisableCmd)
 	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authLockoutsCmd)
 }
 
 // readPassword reads a password from terminal with echo disabled
@@ -150,6 +180,9 @@ func runAuthSetup(_ *cobra.Command, _ []string) error {
 	if username == "" {
 		return fmt.Errorf("username cannot be empty")
 	}
+	if _, err := users.NewStore().Find(username); err == nil {
+		return fmt.Errorf("'%s' is a users.yaml account; choose another name for the config.yaml account", username)
+	}
 
 	// Get password (hidden input)
 	password, err := readPassword("Enter password: ")
@@ -198,7 +231,7 @@ func runAuthSetup(_ *cobra.Command, _ []string) error {
 	ui.Success("Authentication configured successfully!")
 	fmt.Println()
 	ui.Info("Git clients can authenticate using:")
-	ui.Command(fmt.Sprintf("git clone http://%s:<password>@<host>:<port>/repo.git", username))
+	ui.Command(fmt.Sprintf("git clone %s://%s:<password>@<host>:<port>/repo.git", cfg.Scheme(), username))
 	fmt.Println()
 	ui.Info("Or configure Git credential helper:")
 	ui.Command("git config credential.helper store")
@@ -274,10 +307,41 @@ func runAuthStatus(_ *cobra.Command, _ []string) error {
 	ui.Title("Authentication Status")
 	fmt.Println()
 
-	if cfg.AuthEnabled && cfg.AuthUser != "" && cfg.AuthPasswordHash != "" {
+	hasConfigUser := cfg.AuthUser != "" && cfg.AuthPasswordHash != ""
+	userCount, _ := users.NewStore().Count()
+	tokenCount, _ := tokens.NewStore().Count()
+
+	if cfg.AuthEnabled && (hasConfigUser || userCount > 0 || tokenCount > 0) {
 		ui.Success("Authentication: ENABLED")
-		ui.Info("  Username: %s", cfg.AuthUser)
-		ui.Info("  Password: ********")
+		if hasConfigUser {
+			ui.Info("  Username: %s", cfg.AuthUser)
+			ui.Info("  Password: ********")
+		}
+		if userCount > 0 {
+			ui.Info("  User accounts: %d (see 'lgh user list')", userCount)
+		}
+		if tokenCount > 0 {
+			ui.Info("  Access tokens: %d (see 'lgh token list')", tokenCount)
+		}
+
+		// Legacy SHA256 hashes are upgraded on the next successful login
+		var legacy []string
+		if hasConfigUser && server.IsLegacyHash(cfg.AuthPasswordHash) {
+			legacy = append(legacy, cfg.AuthUser)
+		}
+		if list, err := users.NewStore().List(); err == nil {
+			for _, u := range list {
+				if server.IsLegacyHash(u.PasswordHash) {
+					legacy = append(legacy, u.Name)
+				}
+			}
+		}
+		if len(legacy) > 0 {
+			fmt.Println()
+			ui.Warning("Legacy SHA256 password hashes: %s", strings.Join(legacy, ", "))
+			ui.Info("  They are upgraded to argon2id on the next successful login,")
+			ui.Info("  or reset them now with 'lgh auth setup' / 'lgh user passwd <name>'.")
+		}
 	} else {
 		ui.Warning("Authentication: DISABLED")
 		fmt.Println()
@@ -339,3 +403,65 @@ func updateAuthConfig(cfg *config.Config) error {
 	// Write back with secure permissions (0600 = owner read/write only)
 	return os.WriteFile(configPath, []byte(strings.Join(newLines, "\n")), 0600)
 }
+
+func runAuthLockouts(_ *cobra.Command, _ []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	if running, _ := server.IsRunning(); !running {
+		ui.Info("Server is not running. Lockouts are kept in memory and reset on restart.")
+		return nil
+	}
+
+	if lockoutsClear {
+		var resp struct {
+			Cleared int `json:"cleared"`
+		}
+		path := "/lockouts?key=" + url.QueryEscape(lockoutsKey)
+		if err := server.ControlRequest(http.MethodDelete, path, &resp); err != nil {
+			return err
+		}
+		ui.Success("Cleared %d lockout(s)", resp.Cleared)
+		return nil
+	}
+
+	var resp struct {
+		AuthEnabled bool             `json:"auth_enabled"`
+		Lockouts    []server.Lockout `json:"lockouts"`
+	}
+	if err := server.ControlRequest(http.MethodGet, "/lockouts", &resp); err != nil {
+		return err
+	}
+
+	if !resp.AuthEnabled {
+		ui.Warning("Authentication is disabled on the running server.")
+		return nil
+	}
+	if len(resp.Lockouts) == 0 {
+		ui.Success("No failed login attempts recorded.")
+		return nil
+	}
+
+	ui.Title("Failed Logins (%d)", len(resp.Lockouts))
+
+	now := time.Now()
+	table := ui.NewTable([]string{"Key", "Failures", "Status", "Last Failure"})
+	for _, l := range resp.Lockouts {
+		status := ui.Gray("-")
+		if l.Locked(now) {
+			status = ui.Red(fmt.Sprintf("locked (%s)", l.LockedUntil.Sub(now).Round(time.Second)))
+		}
+		table.AddRow([]string{
+			ui.Bold(l.Key),
+			strconv.Itoa(l.Failures),
+			status,
+			ui.Gray(l.LastFailure.Format("2006-01-02 15:04:05")),
+		})
+	}
+	table.Render()
+	fmt.Println()
+	ui.Info("Clear with: lgh auth lockouts --clear [--key <key>]")
+
+	return nil
+}
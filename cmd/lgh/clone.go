@@ -81,7 +81,7 @@ func runClone(_ *cobra.Command, args []string) error {
 			ui.Info("Authentication is enabled. You may need to enter credentials.")
 		}
 
-		cloneURL = fmt.Sprintf("http://%s%s:%d/%s.git", authPrefix, cfg.BindAddress, cfg.Port, repoName)
+		cloneURL = fmt.Sprintf("%s://%s%s:%d/%s.git", cfg.Scheme(), authPrefix, cfg.BindAddress, cfg.Port, repoName)
 		ui.Info("Cloning %s...", cloneURL)
 	}
 
@@ -175,9 +175,9 @@ func printEventLine(line string) {
 		typeColor = ui.Green
 	case event.GitTag:
 		typeColor = ui.Yellow
-	case event.RepoAdded:
+	case event.RepoAdded, event.RepoRenamed:
 		typeColor = ui.Cyan
-	case event.RepoRemoved:
+	case event.RepoRemoved, event.AuthFailed, event.GitPushRejected:
 		typeColor = ui.Red
 	default:
 		typeColor = ui.Gray
@@ -221,10 +221,37 @@ func printEventLine(line string) {
 			}
 			payloadStr = strings.Join(refs, ", ")
 		}
+		if pusher, ok := evt.Payload["pusher"].(string); ok && pusher != "" {
+			payloadStr += ui.Gray(fmt.Sprintf(" by %s", pusher))
+		}
 	} else if evt.Type == event.RepoAdded {
 		if bare, ok := evt.Payload["bare"].(string); ok {
 			payloadStr = filepath.Base(bare)
 		}
+	} else if evt.Type == event.RepoRenamed {
+		from, _ := evt.Payload["from"].(string)
+		payloadStr = "from " + from
+	} else if evt.Type == event.GitPushRejected {
+		payloadStr, _ = evt.Payload["reason"].(string)
+		if paths, ok := evt.Payload["paths"].([]interface{}); ok && len(paths) > 0 {
+			names := make([]string, 0, len(paths))
+			for _, p := range paths {
+				names = append(names, fmt.Sprint(p))
+			}
+			payloadStr += ": " + strings.Join(names, ", ")
+		}
+		if pusher, ok := evt.Payload["pusher"].(string); ok && pusher != "" {
+			payloadStr += ui.Gray(fmt.Sprintf(" by %s", pusher))
+		}
+	} else if evt.Type == event.AuthFailed {
+		ip, _ := evt.Payload["ip"].(string)
+		payloadStr = ip
+		if user, ok := evt.Payload["user"].(string); ok && user != "" {
+			payloadStr = fmt.Sprintf("%s@%s", user, ip)
+		}
+		if until, ok := evt.Payload["locked_until"].(string); ok {
+			payloadStr += ui.Gray(fmt.Sprintf(" locked until %s", until))
+		}
 	}
 
 	fmt.Printf("%s  %-12s  %-15s  %s\n",
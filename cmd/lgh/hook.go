@@ -0,0 +1,84 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JoeGlenn1213/lgh/internal/hooks"
+)
+
+// hookCmd is run by the managed git hooks inside a bare repository.
+// It is hidden because it is not meant to be called by hand.
+var hookCmd = &cobra.Command{
+	Use:           "hook",
+	Short:         "Run a managed git hook (internal)",
+	Hidden:        true,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+var hookPreReceiveCmd = &cobra.Command{
+	Use:           "pre-receive",
+	Short:         "Check pushed refs before they are updated",
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		barePath, err := hooks.BarePathFromEnv()
+		if err != nil {
+			return err
+		}
+		return exitOnRejection(hooks.PreReceive(barePath, os.Stdin, os.Stderr))
+	},
+}
+
+var hookPostReceiveCmd = &cobra.Command{
+	Use:           "post-receive",
+	Short:         "Report pushed ref updates to the server",
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		barePath, err := hooks.BarePathFromEnv()
+		if err != nil {
+			return err
+		}
+		return hooks.PostReceive(barePath, os.Stdin)
+	},
+}
+
+// exitOnRejection exits with status 1 without printing an error again
+// when a hook rejected the push; git shows the hook's own message.
+func exitOnRejection(err error) error {
+	if errors.Is(err, hooks.ErrRejected) {
+		os.Exit(1)
+	}
+	return err
+}
+
+func init() {
+	hookCmd.AddCommand(hookPreReceiveCmd)
+	hookCmd.AddCommand(hookPostReceiveCmd)
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/keys"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+var (
+	keyUser string
+	keyName string
+)
+
+var keyCmd = &cobra.Command{
+	Use:   "key",
+	Short: "Manage SSH keys for git over ssh://",
+	Long: `Manage the SSH public keys accepted by the embedded SSH server.
+
+Each key is mapped to an LGH user, whose repository permissions apply.
+A key added without --user acts as the server owner (full access).
+
+Enable the SSH server with 'ssh_port' in config.yaml or
+'lgh serve --ssh-port <port>', then use remotes such as:
+  git clone ssh://git@<host>:<port>/repo.git`,
+}
+
+var keyAddCmd = &cobra.Command{
+	Use:   "add <public-key-file|->",
+	Short: "Authorize an SSH public key",
+	Long: `Authorize an SSH public key, read from a file or from stdin ('-').
+
+Examples:
+  lgh key add ~/.ssh/id_ed25519.pub
+  lgh key add alice.pub --user alice --name alice-laptop
+  cat ci.pub | lgh key add - --user ci`,
+	Args: cobra.ExactArgs(1),
+	RunE: runKeyAdd,
+}
+
+var keyListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List authorized SSH keys",
+	Aliases: []string{"ls"},
+	RunE:    runKeyList,
+}
+
+var keyRemoveCmd = &cobra.Command{
+	Use:     "remove <name|fingerprint>",
+	Short:   "Remove an authorized SSH key",
+	Aliases: []string{"rm"},
+	Args:    cobra.ExactArgs(1),
+	RunE:    runKeyRemove,
+}
+
+func init() {
+	keyAddCmd.Flags().StringVar(&keyUser, "user", "", "LGH user the key authenticates as (default: server owner)")
+	keyAddCmd.Flags().StringVar(&keyName, "name", "", "Name of the key (default: key comment or file name)")
+
+	keyCmd.AddCommand(keyAddCmd)
+	keyCmd.AddCommand(keyListCmd)
+	keyCmd.AddCommand(keyRemoveCmd)
+}
+
+func runKeyAdd(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if args[0] == "-" {
+		data, err = io.ReadAll(io.LimitReader(os.Stdin, 64*1024))
+	} else {
+		// nolint:gosec // G304: reading a key file chosen by the user
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	pub, comment, err := keys.ParsePublicKey(data)
+	if err != nil {
+		return err
+	}
+
+	if keyUser != "" && keyUser != config.Get().AuthUser {
+		if _, err := users.NewStore().Find(keyUser); err != nil {
+			return fmt.Errorf("user '%s' not found (create it with 'lgh user add %s')", keyUser, keyUser)
+		}
+	}
+
+	name := keyName
+	if name == "" {
+		name = comment
+	}
+	if name == "" && args[0] != "-" {
+		name = strings.TrimSuffix(filepath.Base(args[0]), ".pub")
+	}
+	if name == "" {
+		return fmt.Errorf("the key has no comment; give it a name with --name")
+	}
+
+	key := keys.Key{Name: name, User: keyUser, PublicKey: pub, Comment: comment}
+	if err := keys.NewStore().Add(key); err != nil {
+		return err
+	}
+
+	ui.Success("SSH key '%s' added (%s)", name, key.Fingerprint())
+	fmt.Printf("  Authenticates as: %s\n", key.Identity())
+
+	cfg := config.Get()
+	if cfg.SSHPort == 0 {
+		fmt.Println()
+		ui.Info("The SSH server is disabled. Enable it with 'ssh_port' in config.yaml or:")
+		ui.Command("lgh serve --ssh-port 2222")
+	}
+	return nil
+}
+
+func runKeyList(_ *cobra.Command, _ []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	list, err := keys.NewStore().List()
+	if err != nil {
+		return fmt.Errorf("failed to list keys: %w", err)
+	}
+
+	if len(list) == 0 {
+		ui.Info("No SSH keys added yet.")
+		fmt.Println()
+		ui.Info("Add one:")
+		ui.Command("lgh key add ~/.ssh/id_ed25519.pub")
+		return nil
+	}
+
+	ui.Title("SSH Keys (%d)", len(list))
+
+	table := ui.NewTable([]string{"Name", "User", "Type", "Fingerprint", "Added"})
+	for _, k := range list {
+		user := k.User
+		if user == "" {
+			user = ui.Gray("owner")
+		}
+		added := ""
+		if !k.AddedAt.IsZero() {
+			added = k.AddedAt.Local().Format("2006-01-02 15:04")
+		}
+		table.AddRow([]string{ui.Bold(k.Name), user, k.PublicKey.Type(), k.Fingerprint(), added})
+	}
+
+	table.Render()
+	fmt.Println()
+
+	return nil
+}
+
+func runKeyRemove(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	if err := keys.NewStore().Remove(args[0]); err != nil {
+		return err
+	}
+
+	ui.Success("SSH key '%s' removed", args[0])
+	return nil
+}
@@ -23,6 +23,8 @@ package main
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -38,14 +40,22 @@ var listCmd = &cobra.Command{
 	Long: `List all repositories that have been added to LGH.
 
 Shows repository name, source path, and clone URL.
+Repositories with a namespace are grouped by namespace.
 
 Examples:
-  lgh list           # List all repositories
-  lgh ls             # Short alias`,
+  lgh list                   # List all repositories
+  lgh list --namespace team  # Only repositories in team/
+  lgh ls                     # Short alias`,
 	Aliases: []string{"ls"},
 	RunE:    runList,
 }
 
+var listNamespace string
+
+func init() {
+	listCmd.Flags().StringVar(&listNamespace, "namespace", "", "Only list repositories in this namespace")
+}
+
 func runList(_ *cobra.Command, _ []string) error {
 	// Ensure initialized
 	if err := ensureInitialized(); err != nil {
@@ -59,7 +69,21 @@ func runList(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("failed to list repositories: %w", err)
 	}
 
+	if listNamespace != "" {
+		filtered := repos[:0]
+		for _, repo := range repos {
+			if ns := repo.Namespace(); ns == listNamespace || strings.HasPrefix(ns, listNamespace+"/") {
+				filtered = append(filtered, repo)
+			}
+		}
+		repos = filtered
+	}
+
 	// Check if empty
+	if len(repos) == 0 && listNamespace != "" {
+		ui.Info("No repositories in namespace '%s'.", listNamespace)
+		return nil
+	}
 	if len(repos) == 0 {
 		ui.Info("No repositories registered yet.")
 		fmt.Println()
@@ -70,7 +94,7 @@ func runList(_ *cobra.Command, _ []string) error {
 
 	// Get server info
 	cfg := config.Get()
-	baseURL := fmt.Sprintf("http://%s:%d", cfg.BindAddress, cfg.Port)
+	baseURL := cfg.BaseURL(cfg.BindAddress)
 	serverRunning, _ := server.IsRunning()
 
 	// Print header
@@ -81,18 +105,52 @@ func runList(_ *cobra.Command, _ []string) error {
 		fmt.Println()
 	}
 
-	// Create table
+	// Group by namespace; top-level repositories come first
+	sort.SliceStable(repos, func(i, j int) bool {
+		return repos[i].Namespace() < repos[j].Namespace()
+	})
+	grouped := len(repos) > 0 && repos[len(repos)-1].Namespace() != ""
+
+	for start := 0; start < len(repos); {
+		namespace := repos[start].Namespace()
+		end := start
+		for end < len(repos) && repos[end].Namespace() == namespace {
+			end++
+		}
+
+		if grouped {
+			if namespace == "" {
+				fmt.Println(ui.Bold("(no namespace)"))
+			} else {
+				fmt.Println(ui.Bold(namespace + "/"))
+			}
+		}
+		renderRepoTable(repos[start:end], baseURL, serverRunning, grouped)
+		fmt.Println()
+		start = end
+	}
+
+	return nil
+}
+
+// renderRepoTable prints one table of repositories; shortNames drops the
+// namespace, which is already shown as the group heading
+func renderRepoTable(repos []registry.RepoMapping, baseURL string, serverRunning, shortNames bool) {
 	table := ui.NewTable([]string{"Name", "Source Path", "Clone URL", "Created"})
 
 	for _, repo := range repos {
 		// Check if source path exists
-		sourceExists := "✓"
-		if _, err := os.Stat(repo.SourcePath); os.IsNotExist(err) {
-			sourceExists = "✗ (missing)"
+		source := repo.SourcePath + " " + ui.Gray("✓")
+		if repo.SourcePath == "" && repo.CreatedBy != "" {
+			source = ui.Gray("(created by " + repo.CreatedBy + ")")
+		} else if repo.SourcePath == "" {
+			source = ui.Gray("(no source)")
+		} else if _, err := os.Stat(repo.SourcePath); os.IsNotExist(err) {
+			source = repo.SourcePath + " " + ui.Gray("✗ (missing)")
 		}
 
 		// Build clone URL
-		cloneURL := fmt.Sprintf("%s/lgh/%s.git", baseURL, repo.Name)
+		cloneURL := baseURL + registry.URLPath(repo.Name)
 		if !serverRunning {
 			cloneURL = ui.Gray("(server offline)")
 		}
@@ -100,16 +158,18 @@ func runList(_ *cobra.Command, _ []string) error {
 		// Format created time
 		created := repo.CreatedAt.Format("2006-01-02 15:04")
 
+		name := repo.Name
+		if shortNames {
+			name = repo.ShortName()
+		}
+
 		table.AddRow([]string{
-			ui.Bold(repo.Name),
-			fmt.Sprintf("%s %s", repo.SourcePath, ui.Gray(sourceExists)),
+			ui.Bold(name),
+			source,
 			cloneURL,
 			ui.Gray(created),
 		})
 	}
 
 	table.Render()
-	fmt.Println()
-
-	return nil
 }
@@ -50,7 +50,7 @@ It provides a simple way to host Git repositories locally via HTTP,
 similar to GitHub but running entirely on your machine.
 
 FEATURES:
-  • HTTP Git hosting via git-http-backend
+  • Smart HTTP Git hosting (protocol v2, partial clone)
   • Daemon mode for background operation
   • Built-in authentication for secure sharing
   • mDNS discovery for LAN access
@@ -105,8 +105,15 @@ Git Commit: %s
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(removeCmd)
+	rootCmd.AddCommand(trashCmd)
 	rootCmd.AddCommand(tunnelCmd)
 	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(userCmd)
+	rootCmd.AddCommand(tokenCmd)
+	rootCmd.AddCommand(keyCmd)
+	rootCmd.AddCommand(namespaceCmd)
+	rootCmd.AddCommand(tlsCmd)
+	rootCmd.AddCommand(hookCmd)
 
 	// New in v1.0.4
 	rootCmd.AddCommand(repoCmd)
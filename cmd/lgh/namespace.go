@@ -0,0 +1,221 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+var (
+	createGroup    string
+	createAllUsers bool
+)
+
+var namespaceCmd = &cobra.Command{
+	Use:   "namespace",
+	Short: "List namespaces and control push-to-create",
+	Long: `List repository namespaces and manage who may create repositories by pushing.
+
+Push-to-create is off until a rule allows it. With a rule in place,
+'git push https://host:port/team/newproject.git main' from an allowed
+user creates and registers team/newproject on the fly.
+
+Namespaces:
+  team     repositories under team/ (and nested namespaces like team/web/)
+  /        top-level repositories (https://host:port/lgh/<name>.git)
+  '*'      any namespace, including the top level`,
+}
+
+var namespaceListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List namespaces and push-to-create rules",
+	Args:    cobra.NoArgs,
+	RunE:    runNamespaceList,
+}
+
+// lgh namespace allow-create <namespace> [user] [--group <group>] [--all-users]
+var namespaceAllowCreateCmd = &cobra.Command{
+	Use:   "allow-create <namespace> [user]",
+	Short: "Allow a user or group to create repositories by pushing",
+	Long: `Allow a user or group to create repositories in a namespace by pushing to a new URL.
+
+Created repositories have no source path and no access list, so they
+are open to every authenticated user until restricted with 'lgh repo grant'.
+
+Examples:
+  lgh namespace allow-create team --group devs   # devs may push new team/* repos
+  lgh namespace allow-create alice alice         # alice may create alice/* repos
+  lgh namespace allow-create '*' bob             # bob may create anywhere
+  lgh namespace allow-create / --all-users       # anyone may create top-level repos`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runNamespaceAllowCreate,
+}
+
+// lgh namespace deny-create <namespace> [user] [--group <group>] [--all-users]
+var namespaceDenyCreateCmd = &cobra.Command{
+	Use:   "deny-create <namespace> [user]",
+	Short: "Remove a push-to-create rule",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runNamespaceDenyCreate,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{namespaceAllowCreateCmd, namespaceDenyCreateCmd} {
+		cmd.Flags().StringVar(&createGroup, "group", "", "Apply to a group instead of a user")
+		cmd.Flags().BoolVar(&createAllUsers, "all-users", false, "Apply to every authenticated user")
+	}
+
+	namespaceCmd.AddCommand(namespaceListCmd)
+	namespaceCmd.AddCommand(namespaceAllowCreateCmd)
+	namespaceCmd.AddCommand(namespaceDenyCreateCmd)
+}
+
+// createRuleFromArgs builds a push-to-create rule from the command arguments
+func createRuleFromArgs(args []string) (registry.CreateRule, error) {
+	rule := registry.CreateRule{Namespace: strings.Trim(args[0], "/")}
+	if args[0] == registry.AnyNamespace {
+		rule.Namespace = registry.AnyNamespace
+	}
+
+	subjects := 0
+	if len(args) == 2 {
+		rule.User = args[1]
+		subjects++
+	}
+	if createGroup != "" {
+		rule.Group = createGroup
+		subjects++
+	}
+	if createAllUsers {
+		rule.User = registry.AnyUser
+		subjects++
+	}
+	if subjects != 1 {
+		return rule, fmt.Errorf("specify exactly one of a user, --group or --all-users")
+	}
+
+	if subject := rule.User + rule.Group; subject != registry.AnyUser {
+		if err := users.ValidateName(subject); err != nil {
+			return rule, err
+		}
+	}
+	return rule, nil
+}
+
+func runNamespaceList(_ *cobra.Command, _ []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	reg := registry.New()
+	repos, err := reg.List()
+	if err != nil {
+		return fmt.Errorf("failed to list repositories: %w", err)
+	}
+	rules, err := reg.CreateRules()
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+	for _, repo := range repos {
+		if ns := repo.Namespace(); ns != "" {
+			counts[ns]++
+		}
+	}
+	names := make([]string, 0, len(counts))
+	for ns := range counts {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+
+	ui.Title("Namespaces (%d)", len(names))
+	if len(names) == 0 {
+		ui.Info("No namespaced repositories yet.")
+	} else {
+		table := ui.NewTable([]string{"Namespace", "Repositories"})
+		for _, ns := range names {
+			table.AddRow([]string{ui.Bold(ns + "/"), fmt.Sprintf("%d", counts[ns])})
+		}
+		table.Render()
+	}
+	fmt.Println()
+
+	ui.Title("Push-to-create")
+	if len(rules) == 0 {
+		ui.Info("Disabled. Allow it with:")
+		ui.Command("lgh namespace allow-create <namespace> <user>")
+		return nil
+	}
+	table := ui.NewTable([]string{"Namespace", "Who"})
+	for _, rule := range rules {
+		table.AddRow([]string{rule.NamespaceLabel(), rule.Subject()})
+	}
+	table.Render()
+	fmt.Println()
+
+	return nil
+}
+
+func runNamespaceAllowCreate(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	rule, err := createRuleFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	if err := registry.New().AllowCreate(rule); err != nil {
+		return err
+	}
+
+	ui.Success("%s may now create repositories in %s by pushing", rule.Subject(), rule.NamespaceLabel())
+	return nil
+}
+
+func runNamespaceDenyCreate(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	rule, err := createRuleFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	if err := registry.New().DenyCreate(rule); err != nil {
+		return err
+	}
+
+	ui.Success("Removed push-to-create rule for %s in %s", rule.Subject(), rule.NamespaceLabel())
+	return nil
+}
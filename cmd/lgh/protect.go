@@ -0,0 +1,163 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+var (
+	protectNoForcePush bool
+	protectNoDeletion  bool
+	protectLinear      bool
+	protectAllow       []string
+	protectRemove      bool
+)
+
+// lgh protect <repo> [branch] [--no-force-push] [--no-deletion] [--linear] [--allow ...]
+var protectCmd = &cobra.Command{
+	Use:   "protect <repo> [branch]",
+	Short: "Protect branches against force-push, deletion and more",
+	Long: `Manage branch protection rules of a repository.
+
+Rules are checked by a managed pre-receive hook before any ref is
+updated, so a rejected push changes nothing and the git client shows
+the reason. The branch can be a glob such as 'release/*'.
+
+Without rule flags, a new rule forbids force-push and deletion.
+Running the command again for the same branch replaces its rule.
+
+Rules:
+  --no-force-push      only fast-forward updates
+  --no-deletion        the branch cannot be deleted
+  --linear             no merge commits
+  --allow <names>      only these users (or group:<name>) may push
+
+Examples:
+  lgh protect my-app                      # list rules
+  lgh protect my-app main
+  lgh protect my-app main --linear --allow alice,group:leads
+  lgh protect my-app 'release/*' --no-deletion
+  lgh protect my-app main --remove`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runProtect,
+}
+
+func init() {
+	protectCmd.Flags().BoolVar(&protectNoForcePush, "no-force-push", false, "Reject non-fast-forward updates")
+	protectCmd.Flags().BoolVar(&protectNoDeletion, "no-deletion", false, "Reject deleting the branch")
+	protectCmd.Flags().BoolVar(&protectLinear, "linear", false, "Reject merge commits")
+	protectCmd.Flags().StringSliceVar(&protectAllow, "allow", nil, "Users or group:<name> allowed to push (comma-separated)")
+	protectCmd.Flags().BoolVar(&protectRemove, "remove", false, "Remove the rule for the branch")
+
+	rootCmd.AddCommand(protectCmd)
+}
+
+func runProtect(cmd *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	reg := registry.New()
+	repo, err := reg.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		return listProtections(repo)
+	}
+	branch := strings.TrimPrefix(args[1], "refs/heads/")
+
+	if protectRemove {
+		if err := reg.Unprotect(repo.Name, branch); err != nil {
+			return err
+		}
+		ui.Success("Removed protection from '%s' on '%s'", branch, repo.Name)
+		return nil
+	}
+
+	for _, name := range protectAllow {
+		if err := users.ValidateName(strings.TrimPrefix(name, "group:")); err != nil {
+			return err
+		}
+	}
+
+	rule := registry.BranchProtection{
+		Branch:         branch,
+		NoForcePush:    protectNoForcePush,
+		NoDeletion:     protectNoDeletion,
+		LinearHistory:  protectLinear,
+		AllowedPushers: protectAllow,
+	}
+	ruleFlags := []string{"no-force-push", "no-deletion", "linear", "allow"}
+	anySet := false
+	for _, f := range ruleFlags {
+		anySet = anySet || cmd.Flags().Changed(f)
+	}
+	if !anySet {
+		rule.NoForcePush = true
+		rule.NoDeletion = true
+	}
+
+	// The rules are enforced by the managed pre-receive hook
+	if err := git.InstallHooks(repo.BarePath); err != nil {
+		return err
+	}
+
+	if err := reg.Protect(repo.Name, rule); err != nil {
+		return err
+	}
+
+	ui.Success("Protected '%s' on '%s': %s", branch, repo.Name, rule.Summary())
+	return nil
+}
+
+// listProtections prints the branch protection rules of a repository
+func listProtections(repo *registry.RepoMapping) error {
+	if len(repo.Protections) == 0 {
+		ui.Info("No protected branches on '%s'.", repo.Name)
+		fmt.Println()
+		ui.Info("Protect a branch:")
+		ui.Command(fmt.Sprintf("lgh protect %s main", repo.Name))
+		return nil
+	}
+
+	ui.Title("Protected Branches: %s (%d)", repo.Name, len(repo.Protections))
+
+	table := ui.NewTable([]string{"Branch", "Rules"})
+	for _, p := range repo.Protections {
+		table.AddRow([]string{ui.Bold(p.Branch), p.Summary()})
+	}
+
+	table.Render()
+	fmt.Println()
+
+	return nil
+}
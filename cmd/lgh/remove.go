@@ -23,21 +23,20 @@ package main
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/JoeGlenn1213/lgh/internal/config"
-	"github.com/JoeGlenn1213/lgh/internal/event"
 	"github.com/JoeGlenn1213/lgh/internal/git"
 	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/repos"
 	"github.com/JoeGlenn1213/lgh/pkg/ui"
 )
 
 var (
 	removeForce    bool
 	removeKeepBare bool
+	removePurge    bool
 )
 
 var removeCmd = &cobra.Command{
@@ -46,14 +45,19 @@ var removeCmd = &cobra.Command{
 	Long: `Remove a repository from LGH.
 
 This command:
-  1. Removes the repository from mappings.yaml
-  2. Optionally deletes the bare repository
-  3. Optionally removes the 'lgh' remote from source repo
+  1. Moves the bare repository to the trash (see 'lgh trash')
+  2. Moves the repository from mappings.yaml to its trash list
+  3. Removes the 'lgh' remote from source repo
+
+Trashed repositories keep their commit statuses and LFS objects and can be
+restored with 'lgh trash restore' until they are purged, automatically
+after trash_retention_days (default 30).
 
 Examples:
-  lgh remove my-app            # Remove with confirmation
-  lgh remove my-app --force    # Remove without confirmation
-  lgh remove my-app --keep-bare # Keep the bare repository`,
+  lgh remove my-app             # Move to trash with confirmation
+  lgh remove my-app --force     # Move to trash without confirmation
+  lgh remove my-app --purge     # Delete the bare repository immediately
+  lgh remove my-app --keep-bare # Unregister, keep the bare repository in place`,
 	Aliases: []string{"rm", "delete"},
 	Args:    cobra.ExactArgs(1),
 	RunE:    runRemove,
@@ -62,38 +66,7 @@ Examples:
 func init() {
 	removeCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "Skip confirmation")
 	removeCmd.Flags().BoolVar(&removeKeepBare, "keep-bare", false, "Keep the bare repository")
-}
-
-// isPathSafe checks if targetPath is safely within basePath
-// SECURITY: Prevents path traversal attacks
-func isPathSafe(basePath, targetPath string) bool {
-	// Resolve to absolute paths
-	absBase, err := filepath.Abs(basePath)
-	if err != nil {
-		return false
-	}
-	absTarget, err := filepath.Abs(targetPath)
-	if err != nil {
-		return false
-	}
-
-	// Resolve any symlinks
-	realBase, err := filepath.EvalSymlinks(absBase)
-	if err != nil {
-		realBase = absBase
-	}
-	realTarget, err := filepath.EvalSymlinks(absTarget)
-	if err != nil {
-		// Target might not exist yet, use absolute path
-		realTarget = absTarget
-	}
-
-	// Ensure target is within base (with trailing slash to prevent prefix attacks)
-	if !strings.HasSuffix(realBase, string(os.PathSeparator)) {
-		realBase += string(os.PathSeparator)
-	}
-
-	return strings.HasPrefix(realTarget, realBase) || realTarget == strings.TrimSuffix(realBase, string(os.PathSeparator))
+	removeCmd.Flags().BoolVar(&removePurge, "purge", false, "Delete the bare repository instead of moving it to the trash")
 }
 
 func runRemove(_ *cobra.Command, args []string) error {
@@ -103,6 +76,9 @@ func runRemove(_ *cobra.Command, args []string) error {
 	}
 
 	name := args[0]
+	if removeKeepBare && removePurge {
+		return fmt.Errorf("--keep-bare and --purge cannot be used together")
+	}
 
 	// Find repository
 	reg := registry.New()
@@ -113,7 +89,7 @@ func runRemove(_ *cobra.Command, args []string) error {
 
 	// SECURITY: Validate BarePath is within the configured ReposDir
 	cfg := config.Get()
-	if !isPathSafe(cfg.ReposDir, repo.BarePath) {
+	if !registry.IsPathSafe(cfg.ReposDir, repo.BarePath) {
 		return fmt.Errorf("security error: bare repository path '%s' is outside of repos directory '%s'",
 			repo.BarePath, cfg.ReposDir)
 	}
@@ -127,8 +103,10 @@ func runRemove(_ *cobra.Command, args []string) error {
 	// Confirm if not forced
 	if !removeForce {
 		ui.Warning("This will remove the repository from LGH.")
-		if !removeKeepBare {
+		if removePurge {
 			ui.Warning("The bare repository at %s will be DELETED.", repo.BarePath)
+		} else if !removeKeepBare {
+			ui.Warning("The bare repository will be moved to the trash.")
 		}
 		fmt.Println()
 
@@ -142,6 +120,33 @@ func runRemove(_ *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	// Move to trash first, so that a failure leaves everything in place
+	mode := repos.MoveToTrash
+	switch {
+	case removePurge:
+		mode = repos.Purge
+		ui.Info("Deleting bare repository...")
+	case removeKeepBare:
+		mode = repos.KeepBare
+	default:
+		ui.Info("Moving bare repository to trash...")
+	}
+	removal, err := repos.Remove(reg, cfg, repo, mode, "")
+	if err != nil {
+		return err
+	}
+	switch {
+	case removal.TrashPath != "":
+		ui.Success("Moved to %s", removal.TrashPath)
+	case removal.Deleted:
+		ui.Success("Deleted %s", repo.BarePath)
+	case removePurge:
+		ui.Warning("Path is not a valid bare repository, skipping deletion for safety")
+	}
+	if removal.TrashPath == "" {
+		ui.Success("Removed from mappings.yaml")
+	}
+
 	// Remove 'lgh' remote from source repository if it exists
 	if _, err := os.Stat(repo.SourcePath); err == nil {
 		ui.Info("Removing 'lgh' remote from source repository...")
@@ -152,38 +157,22 @@ func runRemove(_ *cobra.Command, args []string) error {
 		}
 	}
 
-	// Delete bare repository
-	if !removeKeepBare {
-		ui.Info("Deleting bare repository...")
-
-		// Double-check the path is a git bare repository before deleting
-		if !git.IsBareRepo(repo.BarePath) {
-			ui.Warning("Path is not a valid bare repository, skipping deletion for safety")
-		} else {
-			if err := os.RemoveAll(repo.BarePath); err != nil {
-				ui.Warning("Could not delete bare repository: %v", err)
-			} else {
-				ui.Success("Deleted %s", repo.BarePath)
-			}
-		}
+	fmt.Println()
+	if removal.TrashPath == "" {
+		ui.Success("Repository '%s' removed successfully!", name)
+		fmt.Println()
+		return nil
 	}
 
-	// Remove from registry
-	ui.Info("Removing from registry...")
-	if err := reg.Remove(name); err != nil {
-		return fmt.Errorf("failed to remove from registry: %w", err)
+	ui.Success("Repository '%s' moved to the trash", name)
+	if cfg.TrashRetentionDays > 0 {
+		ui.Info("It will be purged after %d days. To restore it:", cfg.TrashRetentionDays)
+	} else {
+		ui.Info("To restore it:")
 	}
-	ui.Success("Removed from mappings.yaml")
-
-	fmt.Println()
-	ui.Success("Repository '%s' removed successfully!", name)
-
-	// Publish Event
-	event.Publish(event.RepoRemoved, name, map[string]interface{}{
-		"source": repo.SourcePath,
-		"bare":   repo.BarePath,
-	})
+	ui.Command(fmt.Sprintf("lgh trash restore %s", name))
 	fmt.Println()
 
+	purgeExpiredTrash(reg, cfg)
 	return nil
 }
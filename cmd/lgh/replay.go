@@ -32,6 +32,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/JoeGlenn1213/lgh/internal/certs"
 	"github.com/JoeGlenn1213/lgh/internal/config"
 	"github.com/JoeGlenn1213/lgh/internal/event"
 	"github.com/JoeGlenn1213/lgh/pkg/ui"
@@ -80,8 +81,8 @@ func runReplay(_ *cobra.Command, _ []string) error {
 	// 3. Send to Server via HTTP
 	ui.Info("Replaying %d events to %s...", len(events), "localhost")
 
-	serverURL := fmt.Sprintf("http://127.0.0.1:%d/debug/events", cfg.Port)
-	client := &http.Client{Timeout: 2 * time.Second}
+	serverURL := cfg.BaseURL("127.0.0.1") + "/debug/events"
+	client := certs.NewClient(2 * time.Second)
 	successCount := 0
 
 	for _, evt := range events {
@@ -67,10 +67,63 @@ var repoSetDefaultCmd = &cobra.Command{
 	RunE:  runRepoSetDefault,
 }
 
+// lgh repo scan-policy <name> [block|warn|off]
+var repoScanPolicyCmd = &cobra.Command{
+	Use:   "scan-policy <name> [block|warn|off]",
+	Short: "Show or set how pushes with sensitive or large files are handled",
+	Long: `Show or set the push scan policy of a repository.
+
+The server checks every push with the same rules as 'lgh up': sensitive
+files (.env, *.key, *.pem, ...), dependency directories (node_modules, ...)
+and size limits (50MB per file, 200MB per push).
+
+Policies:
+  block   reject the push and list the offending paths
+  warn    accept the push but show the findings to the pusher (default)
+  off     do not scan`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRepoScanPolicy,
+}
+
+// lgh repo configure [name] [--all]
+var repoConfigureCmd = &cobra.Command{
+	Use:   "configure [name]",
+	Short: "Apply LGH serving configuration and build bitmaps and commit-graphs",
+	Long: `Apply the configuration LGH sets on new repositories to existing ones,
+so that partial clones (--filter=blob:none) and shallow fetches (--depth)
+work, then repack with a reachability bitmap and write a commit-graph.
+
+After that the server keeps bitmaps and commit-graphs current after pushes.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRepoConfigure,
+}
+
+var repoConfigureAll bool
+
+// lgh repo daemon-export <name> [on|off]
+var repoDaemonExportCmd = &cobra.Command{
+	Use:   "daemon-export <name> [on|off]",
+	Short: "Show or set whether a repository is served over git://",
+	Long: `Show or set whether a repository can be cloned over the read-only git://
+listener (enabled with 'git_daemon_port' or 'lgh serve --git-daemon-port').
+
+git:// has no authentication: anyone who can reach the port can clone an
+exported repository. Only export repositories on trusted networks.
+Repositories with an access list cannot be exported and are never served.
+The setting is the standard git-daemon-export-ok marker in the bare repository.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRepoDaemonExport,
+}
+
 func init() {
+	repoConfigureCmd.Flags().BoolVar(&repoConfigureAll, "all", false, "Configure every registered repository")
+
 	repoCmd.AddCommand(repoStatusCmd)
 	repoCmd.AddCommand(repoInspectCmd)
 	repoCmd.AddCommand(repoSetDefaultCmd)
+	repoCmd.AddCommand(repoScanPolicyCmd)
+	repoCmd.AddCommand(repoConfigureCmd)
+	repoCmd.AddCommand(repoDaemonExportCmd)
 }
 
 func runRepoStatus(_ *cobra.Command, _ []string) error {
@@ -196,10 +249,40 @@ func runRepoInspect(_ *cobra.Command, args []string) error {
 	ui.Title("Inspecting %s", name)
 
 	ui.Info("📦 Repo: %s", filepath.Base(repo.BarePath))
-	ui.Info("📂 Source: %s", repo.SourcePath)
+	if repo.Description != "" {
+		ui.Info("📝 About:  %s", repo.Description)
+	}
+	if repo.SourcePath == "" && repo.CreatedBy != "" {
+		ui.Info("📂 Source: (none, created by %s)", repo.CreatedBy)
+	} else if repo.SourcePath == "" {
+		ui.Info("📂 Source: (none)")
+	} else {
+		ui.Info("📂 Source: %s", repo.SourcePath)
+	}
 	ui.Info("🏰 Bare:   %s", repo.BarePath)
 	fmt.Println()
 
+	// Access list
+	if repo.Restricted() {
+		ui.Info("🔒 Access:")
+		for _, e := range repo.ACL {
+			ui.Info("  - %s : %s", e.Subject(), e.Permission)
+		}
+	} else {
+		ui.Info("🔓 Access: all authenticated users")
+	}
+	ui.Info("🔍 Push scan: %s", repo.EffectiveScanPolicy())
+	if git.IsDaemonExported(repo.BarePath) {
+		ui.Info("📡 git:// export: on")
+	}
+	if len(repo.Protections) > 0 {
+		ui.Info("🛡️  Protected branches:")
+		for _, p := range repo.Protections {
+			ui.Info("  - %s : %s", p.Branch, p.Summary())
+		}
+	}
+	fmt.Println()
+
 	ui.Info("🧠 Bare Repo Info:")
 
 	// HEAD
@@ -226,10 +309,10 @@ func runRepoInspect(_ *cobra.Command, args []string) error {
 		if err != nil {
 			ui.Gray("  No commits yet or failed to read log.")
 		} else {
-			fmt.Printf("  - Commit : %s\n", commit.Hash)
+			fmt.Printf("  - Commit : %.7s\n", commit.SHA)
 			fmt.Printf("  - Author : %s\n", commit.Author)
-			fmt.Printf("  - Time   : %s\n", commit.Date)
-			fmt.Printf("  - Msg    : %s\n", commit.Msg)
+			fmt.Printf("  - Time   : %s\n", commit.CommittedAt.Local().Format("2006-01-02 15:04:05"))
+			fmt.Printf("  - Msg    : %s\n", commit.Subject)
 		}
 	}
 	fmt.Println()
@@ -256,3 +339,137 @@ func runRepoSetDefault(_ *cobra.Command, args []string) error {
 	ui.Success("Default branch updated.")
 	return nil
 }
+
+func runRepoScanPolicy(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	reg := registry.New()
+	repo, err := reg.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		ui.Info("Scan policy of '%s': %s", repo.Name, repo.EffectiveScanPolicy())
+		return nil
+	}
+
+	policy, err := registry.ParseScanPolicy(args[1])
+	if err != nil {
+		return err
+	}
+
+	// The scan runs in the managed pre-receive hook
+	if err := git.InstallHooks(repo.BarePath); err != nil {
+		return err
+	}
+
+	if err := reg.SetScanPolicy(repo.Name, policy); err != nil {
+		return err
+	}
+
+	ui.Success("Scan policy of '%s' set to %s", repo.Name, policy)
+	return nil
+}
+
+func runRepoConfigure(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	if repoConfigureAll == (len(args) == 1) {
+		return fmt.Errorf("specify a repository name or --all")
+	}
+
+	reg := registry.New()
+	var repos []registry.RepoMapping
+	if repoConfigureAll {
+		all, err := reg.List()
+		if err != nil {
+			return err
+		}
+		repos = all
+	} else {
+		repo, err := reg.Find(args[0])
+		if err != nil {
+			return err
+		}
+		repos = []registry.RepoMapping{*repo}
+	}
+
+	failed := 0
+	for _, repo := range repos {
+		if err := git.ConfigureServing(repo.BarePath); err != nil {
+			ui.Error("%s: %v", repo.Name, err)
+			failed++
+			continue
+		}
+		if err := git.Repack(repo.BarePath); err != nil {
+			ui.Error("%s: %v", repo.Name, err)
+			failed++
+			continue
+		}
+		ui.Success("%s: configured, bitmap and commit-graph written", repo.Name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d repositories failed", failed, len(repos))
+	}
+	return nil
+}
+
+func runRepoDaemonExport(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	reg := registry.New()
+	repo, err := reg.Find(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		state := "off"
+		if git.IsDaemonExported(repo.BarePath) {
+			state = "on"
+		}
+		ui.Info("git:// export of '%s': %s", repo.Name, state)
+		return nil
+	}
+
+	var export bool
+	switch strings.ToLower(args[1]) {
+	case "on":
+		export = true
+	case "off":
+		export = false
+	default:
+		return fmt.Errorf("invalid value '%s': must be on or off", args[1])
+	}
+
+	if export && repo.Restricted() {
+		// git:// has no authentication, so it cannot enforce the access list
+		return fmt.Errorf("'%s' has an access list and cannot be served over git:// without authentication", repo.Name)
+	}
+
+	if err := git.SetDaemonExport(repo.BarePath, export); err != nil {
+		return err
+	}
+
+	if !export {
+		ui.Success("'%s' is no longer served over git://", repo.Name)
+		return nil
+	}
+
+	ui.Success("'%s' is served over git:// (read-only, no authentication)", repo.Name)
+	cfg := config.Get()
+	if cfg.GitDaemonPort > 0 {
+		fmt.Printf("  git clone git://%s:%d/%s.git\n", cfg.BindAddress, cfg.GitDaemonPort, repo.Name)
+	} else {
+		ui.Info("Enable the listener with 'git_daemon_port' in config.yaml or 'lgh serve --git-daemon-port <port>'")
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+var (
+	grantPerm  string
+	grantGroup string
+)
+
+// lgh repo grant <name> [user] --perm read|write|admin [--group <group>]
+var repoGrantCmd = &cobra.Command{
+	Use:   "grant <name> [user]",
+	Short: "Grant a user or group access to a repository",
+	Long: `Grant a user or group access to a repository.
+
+Once a repository has at least one access entry, only the listed users
+and groups can see it. Repositories without entries stay open to every
+authenticated user. The config.yaml account always has full access.
+
+Permissions:
+  read    clone and fetch
+  write   read + push
+  admin   write + API changes
+
+Examples:
+  lgh repo grant client-site alice --perm write
+  lgh repo grant client-site bob
+  lgh repo grant client-site --group devs --perm read`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRepoGrant,
+}
+
+// lgh repo revoke <name> [user] [--group <group>]
+var repoRevokeCmd = &cobra.Command{
+	Use:   "revoke <name> [user]",
+	Short: "Revoke a user's or group's access to a repository",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runRepoRevoke,
+}
+
+func init() {
+	repoGrantCmd.Flags().StringVar(&grantPerm, "perm", "read", "Permission: read, write or admin")
+	repoGrantCmd.Flags().StringVar(&grantGroup, "group", "", "Grant to a group instead of a user")
+	repoRevokeCmd.Flags().StringVar(&grantGroup, "group", "", "Revoke from a group instead of a user")
+
+	repoCmd.AddCommand(repoGrantCmd)
+	repoCmd.AddCommand(repoRevokeCmd)
+}
+
+// aclEntryFromArgs builds the user or group subject of an access entry
+func aclEntryFromArgs(args []string) (registry.ACLEntry, error) {
+	var entry registry.ACLEntry
+	switch {
+	case len(args) == 2 && grantGroup != "":
+		return entry, fmt.Errorf("specify either a user or --group, not both")
+	case len(args) == 2:
+		entry.User = args[1]
+	case grantGroup != "":
+		entry.Group = grantGroup
+	default:
+		return entry, fmt.Errorf("specify a user or --group")
+	}
+
+	subject := entry.User
+	if entry.Group != "" {
+		subject = entry.Group
+	}
+	if err := users.ValidateName(subject); err != nil {
+		return entry, err
+	}
+	return entry, nil
+}
+
+func runRepoGrant(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	name := args[0]
+	entry, err := aclEntryFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	entry.Permission, err = registry.ParsePermission(grantPerm)
+	if err != nil {
+		return err
+	}
+
+	if entry.User != "" {
+		if _, err := users.NewStore().Find(entry.User); err != nil && entry.User != config.Get().AuthUser {
+			ui.Warning("User '%s' does not exist yet. Create it with 'lgh user add %s'.", entry.User, entry.User)
+		}
+	}
+
+	reg := registry.New()
+	if err := reg.Grant(name, entry); err != nil {
+		return err
+	}
+
+	ui.Success("Granted %s access on '%s' to %s", entry.Permission, name, entry.Subject())
+	if repo, err := reg.Find(name); err == nil && git.IsDaemonExported(repo.BarePath) {
+		ui.Warning("'%s' is exported to git://, which cannot check access lists; it is no longer served there", name)
+	}
+
+	if !config.Get().AuthEnabled {
+		ui.Warning("Authentication is disabled, so access lists are not enforced.")
+		ui.Info("Enable it with:")
+		ui.Command("lgh user add <name>")
+	}
+
+	return nil
+}
+
+func runRepoRevoke(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	name := args[0]
+	entry, err := aclEntryFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	reg := registry.New()
+	if err := reg.Revoke(name, entry); err != nil {
+		return err
+	}
+
+	ui.Success("Revoked access on '%s' from %s", name, entry.Subject())
+
+	repo, err := reg.Find(name)
+	if err == nil && !repo.Restricted() {
+		ui.Info("'%s' has no access entries left and is open to all authenticated users.", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/event"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/lfs"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/repos"
+	"github.com/JoeGlenn1213/lgh/internal/tokens"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+var renameRedirectDays int
+
+// lgh repo rename <old> <new> [--redirect-days N]
+var repoRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a repository or move it to another namespace",
+	Long: `Rename a repository or move it to another namespace.
+
+The bare repository, commit statuses and LFS objects move with it, the
+'lgh' remote of the source repository is rewritten, and tokens restricted
+to the repository follow the new name. Existing clones keep working: the
+old URL redirects to the new one for rename_redirect_days (default 30).
+
+Examples:
+  lgh repo rename api client-api
+  lgh repo rename scratch alice/scratch
+  lgh repo rename old-site team/site --redirect-days 0   # no redirect`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRepoRename,
+}
+
+func init() {
+	repoRenameCmd.Flags().IntVar(&renameRedirectDays, "redirect-days", -1, "Days the old URL keeps redirecting (default: rename_redirect_days from config)")
+	repoCmd.AddCommand(repoRenameCmd)
+}
+
+func runRepoRename(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	oldName := args[0]
+	newName := strings.TrimSuffix(strings.Trim(args[1], "/"), ".git")
+	if err := registry.ValidateName(newName); err != nil {
+		return err
+	}
+
+	reg := registry.New()
+	repo, err := reg.Find(oldName)
+	if err != nil {
+		return fmt.Errorf("repository '%s' not found", oldName)
+	}
+	if reg.Exists(newName) {
+		return fmt.Errorf("repository '%s' already exists", newName)
+	}
+
+	cfg := config.Get()
+	if !registry.IsPathSafe(cfg.ReposDir, repo.BarePath) {
+		return fmt.Errorf("security error: bare repository path '%s' is outside of repos directory '%s'",
+			repo.BarePath, cfg.ReposDir)
+	}
+	newBarePath := registry.BarePathFor(cfg.ReposDir, newName)
+	if _, err := os.Stat(newBarePath); err == nil {
+		return fmt.Errorf("bare repository already exists at %s", newBarePath)
+	}
+
+	days := cfg.RenameRedirectDays
+	if renameRedirectDays >= 0 {
+		days = renameRedirectDays
+	}
+
+	ui.Title("Rename Repository: %s -> %s", oldName, newName)
+
+	// Move the bare repository
+	if err := os.MkdirAll(filepath.Dir(newBarePath), 0700); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.Rename(repo.BarePath, newBarePath); err != nil {
+		return fmt.Errorf("failed to move bare repository: %w", err)
+	}
+	if err := reg.Rename(oldName, newName, newBarePath, time.Duration(days)*24*time.Hour); err != nil {
+		// Put the bare repository back so the registry stays consistent
+		_ = os.Rename(newBarePath, repo.BarePath)
+		return fmt.Errorf("failed to update registry: %w", err)
+	}
+	repos.RemoveEmptyNamespaceDirs(cfg.ReposDir, repo.BarePath)
+	ui.Success("Moved %s -> %s", repo.BarePath, newBarePath)
+
+	// Data kept outside the bare repository
+	if err := git.NewStatusStore(cfg.DataDir).Rename(oldName, newName); err != nil {
+		ui.Warning("Failed to move commit statuses: %v", err)
+	}
+	if err := lfs.NewStore(lfs.GetLFSDir(cfg.DataDir)).Rename(oldName, newName); err != nil {
+		ui.Warning("Failed to move LFS objects: %v", err)
+	}
+	if err := tokens.NewStore().RenameRepo(oldName, newName); err != nil {
+		ui.Warning("Failed to update repository tokens: %v", err)
+	}
+
+	// Point the source repository at the new URL
+	newURL := cfg.BaseURL(cfg.BindAddress) + registry.URLPath(newName)
+	if repo.SourcePath != "" {
+		if remoteURL, err := git.GetRemoteURL(repo.SourcePath, "lgh"); err == nil {
+			if rewritten := renamedRemoteURL(remoteURL, newName); rewritten == "" {
+				ui.Warning("Could not rewrite remote 'lgh' (%s), update it manually", remoteURL)
+			} else if err := git.AddRemote(repo.SourcePath, "lgh", rewritten); err != nil {
+				ui.Warning("Failed to update remote: %v", err)
+			} else {
+				ui.Success("Updated remote 'lgh' in %s", repo.SourcePath)
+			}
+		}
+	}
+
+	event.Publish(event.RepoRenamed, newName, map[string]interface{}{
+		"from": oldName,
+		"to":   newName,
+		"bare": newBarePath,
+		"url":  newURL,
+	})
+
+	fmt.Println()
+	ui.Success("Repository '%s' renamed to '%s'", oldName, newName)
+	if days > 0 {
+		ui.Info("The old URL redirects to the new one for %d days. Other clones can update with:", days)
+	} else {
+		ui.Info("Other clones must update their remote:")
+	}
+	ui.Command(fmt.Sprintf("git remote set-url <remote> %s", newURL))
+
+	return nil
+}
+
+// renamedRemoteURL replaces the repository path of an lgh remote URL,
+// keeping its scheme, credentials and host. It returns "" for URLs it cannot parse.
+func renamedRemoteURL(remoteURL, newName string) string {
+	u, err := url.Parse(remoteURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	u.Path = registry.URLPath(newName)
+	return u.String()
+}
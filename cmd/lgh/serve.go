@@ -35,12 +35,14 @@ import (
 )
 
 var (
-	readOnlyFlag bool
-	enableMDNS   bool
-	serverPort   int
-	bindAddress  string
-	daemonFlag   bool
-	allowUnsafe  bool
+	readOnlyFlag  bool
+	enableMDNS    bool
+	serverPort    int
+	bindAddress   string
+	daemonFlag    bool
+	allowUnsafe   bool
+	gitDaemonPort int
+	sshPort       int
 )
 
 var serveCmd = &cobra.Command{
@@ -52,6 +54,9 @@ The server listens on localhost by default (127.0.0.1:9418).
 Use --read-only to prevent push operations.
 Use --mdns to enable mDNS for local network discovery.
 Use --daemon to run in background mode.
+Use --git-daemon-port to also serve read-only git:// for exported repos
+(see 'lgh repo daemon-export').
+Use --ssh-port to also serve git over SSH (see 'lgh key').
 
 Examples:
   lgh serve                    # Start with defaults
@@ -59,6 +64,8 @@ Examples:
   lgh serve --read-only        # Start in read-only mode
   lgh serve --port 8080        # Use custom port
   lgh serve --mdns             # Enable mDNS discovery
+  lgh serve --git-daemon-port 9419 # Anonymous git:// clones of exported repos
+  lgh serve --ssh-port 2222    # git over ssh:// with 'lgh key' keys
   lgh serve --bind 0.0.0.0 --allow-unsafe # Allow public access (DANGEROUS)`,
 	RunE: runServe,
 }
@@ -70,6 +77,8 @@ func init() {
 	serveCmd.Flags().StringVarP(&bindAddress, "bind", "b", "", "Address to bind to (default: 127.0.0.1)")
 	serveCmd.Flags().BoolVarP(&daemonFlag, "daemon", "d", false, "Run server in background (daemon mode)")
 	serveCmd.Flags().BoolVar(&allowUnsafe, "allow-unsafe", false, "Allow binding to non-localhost without auth/read-only")
+	serveCmd.Flags().IntVar(&gitDaemonPort, "git-daemon-port", 0, "Also serve read-only git:// on this port (default: git_daemon_port, 0 = off)")
+	serveCmd.Flags().IntVar(&sshPort, "ssh-port", 0, "Also serve git over SSH on this port (default: ssh_port, 0 = off)")
 }
 
 func runServe(_ *cobra.Command, _ []string) error {
@@ -99,11 +108,21 @@ func runServe(_ *cobra.Command, _ []string) error {
 	if enableMDNS {
 		cfg.MDNSEnabled = true
 	}
+	if gitDaemonPort > 0 {
+		cfg.GitDaemonPort = gitDaemonPort
+	}
+	if sshPort > 0 {
+		cfg.SSHPort = sshPort
+	}
 
 	// Security Validation for non-localhost bindings
 	isLocalhost := cfg.BindAddress == "127.0.0.1" || cfg.BindAddress == "localhost"
 	isSafeMode := cfg.AuthEnabled || cfg.ReadOnly
 
+	if !isLocalhost && cfg.AuthEnabled && !cfg.TLSEnabled() {
+		ui.Warning("Passwords will be sent in cleartext over the network. Run 'lgh tls init' to enable HTTPS.")
+	}
+
 	if !isLocalhost {
 		if !isSafeMode && !allowUnsafe {
 			return fmt.Errorf("SECURITY ERROR: Binding to %s exposes LGH to the network without protection.\n"+
@@ -131,7 +150,7 @@ func runServe(_ *cobra.Command, _ []string) error {
 
 	// Start mDNS if enabled
 	if cfg.MDNSEnabled {
-		mdnsService, err := mdns.NewService(cfg.Port)
+		mdnsService, err := mdns.NewService(cfg.Port, cfg.TLSEnabled())
 		if err != nil {
 			ui.Warning("Failed to initialize mDNS: %v", err)
 		} else {
@@ -169,6 +188,12 @@ func startDaemon(cfg *config.Config) error {
 	if cfg.MDNSEnabled {
 		args = append(args, "--mdns")
 	}
+	if cfg.GitDaemonPort > 0 {
+		args = append(args, "--git-daemon-port", fmt.Sprintf("%d", cfg.GitDaemonPort))
+	}
+	if cfg.SSHPort > 0 {
+		args = append(args, "--ssh-port", fmt.Sprintf("%d", cfg.SSHPort))
+	}
 
 	// Get executable path — use os.Executable() to ensure the child daemon
 	// runs the exact same binary as the parent (avoids PATH picking up stale copies)
@@ -192,7 +217,7 @@ func startDaemon(cfg *config.Config) error {
 	}
 
 	ui.Success("LGH server started in background (PID: %d)", cmd.Process.Pid)
-	ui.Info("Address: http://%s:%d", cfg.BindAddress, cfg.Port)
+	ui.Info("Address: %s", cfg.BaseURL(cfg.BindAddress))
 	ui.Info("Use 'lgh stop' to stop the server")
 	ui.Info("Use 'lgh status' to check server status")
 
@@ -23,12 +23,14 @@ package main
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/mdns"
 	"github.com/JoeGlenn1213/lgh/internal/registry"
 	"github.com/JoeGlenn1213/lgh/internal/server"
 	"github.com/JoeGlenn1213/lgh/pkg/ui"
@@ -100,7 +102,7 @@ func runStatus(_ *cobra.Command, _ []string) error {
 	// Health check if server is running
 	if running {
 		ui.Info("Health Check:")
-		healthURL := fmt.Sprintf("http://%s:%d/health", cfg.BindAddress, cfg.Port)
+		healthURL := cfg.BaseURL(cfg.BindAddress) + "/health"
 
 		conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", cfg.BindAddress, cfg.Port), 2*time.Second)
 		if err != nil {
@@ -110,6 +112,18 @@ func runStatus(_ *cobra.Command, _ []string) error {
 			ui.Success("  %s - OK", healthURL)
 		}
 		fmt.Println()
+
+		var queue server.GitQueueStatus
+		if err := server.ControlRequest(http.MethodGet, "/git-queue", &queue); err == nil {
+			ui.Info("Git Processes:")
+			if queue.Limited {
+				fmt.Printf("  %-15s %d / %d\n", "Running:", queue.Running, queue.MaxProcesses)
+				fmt.Printf("  %-15s %d / %d\n", "Queued:", queue.Queued, queue.MaxQueue)
+			} else {
+				fmt.Printf("  %-15s %s\n", "Limit:", "unlimited (max_git_processes: 0)")
+			}
+			fmt.Println()
+		}
 	}
 
 	// Disk usage
@@ -134,8 +148,7 @@ func runStatus(_ *cobra.Command, _ []string) error {
 
 		// Show mDNS URL if enabled
 		if cfg.MDNSEnabled {
-			hostname, _ := os.Hostname()
-			fmt.Printf("  mDNS:      %s\n", ui.URL(fmt.Sprintf("http://%s.local:%d", hostname, cfg.Port)))
+			fmt.Printf("  mDNS:      %s\n", ui.URL(cfg.BaseURL(mdns.Hostname()+".local")))
 		}
 		fmt.Println()
 	}
@@ -0,0 +1,401 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JoeGlenn1213/lgh/internal/certs"
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/mdns"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/server"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+var tlsCmd = &cobra.Command{
+	Use:   "tls",
+	Short: "Manage HTTPS certificates",
+	Long: `Manage HTTPS for the LGH server.
+
+LGH can serve HTTPS with certificates issued by a local certificate
+authority (CA). This keeps Basic-auth passwords and tokens off the wire
+when serving on the LAN with --bind 0.0.0.0.
+Certificates are stored in ~/.localgithub/tls.
+
+Subcommands:
+  lgh tls init                      Create the local CA and a server certificate
+  lgh tls trust                     Show how to make git trust the local CA
+  lgh tls client-cert create <name> Issue a client certificate
+  lgh tls client-cert list          List client certificates
+  lgh tls client-cert revoke <name> Revoke a name's client certificates`,
+}
+
+var tlsInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create the local CA and a server certificate",
+	Long: `Create a local CA (if missing) and issue a server certificate covering
+localhost, the mDNS hostname and this machine's LAN IPs, then enable TLS.
+
+Run again with --force after the machine's IP addresses change.`,
+	RunE: runTLSInit,
+}
+
+var tlsTrustCmd = &cobra.Command{
+	Use:   "trust",
+	Short: "Show how to make git trust the local CA",
+	RunE:  runTLSTrust,
+}
+
+var tlsClientCertCmd = &cobra.Command{
+	Use:   "client-cert",
+	Short: "Manage client certificates",
+	Long: `Manage client certificates for mutual TLS.
+
+A client certificate authenticates as the user named by its CommonName,
+so machines can push and fetch without passwords in .git-credentials.
+Access lists apply as for password logins. A certificate issued to the
+config.yaml account has owner rights; one issued to a user stops working
+when the user is removed.`,
+}
+
+var tlsClientCertCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Issue a client certificate",
+	Long: `Issue a client certificate for <name> signed by the local CA and
+enable client certificate authentication if it is not enabled yet.
+
+The certificate and key are written to ~/.localgithub/tls/clients/.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTLSClientCertCreate,
+}
+
+var tlsClientCertListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List client certificates",
+	Aliases: []string{"ls"},
+	RunE:    runTLSClientCertList,
+}
+
+var tlsClientCertRevokeCmd = &cobra.Command{
+	Use:   "revoke <name>",
+	Short: "Revoke all client certificates issued to a name",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTLSClientCertRevoke,
+}
+
+var (
+	tlsInitForce      bool
+	tlsInitHosts      []string
+	tlsClientCertDays int
+)
+
+func init() {
+	tlsInitCmd.Flags().BoolVar(&tlsInitForce, "force", false, "Reissue the server certificate even if one exists")
+	tlsInitCmd.Flags().StringSliceVar(&tlsInitHosts, "host", nil, "Extra hostname or IP to include in the certificate (repeatable)")
+	tlsClientCertCreateCmd.Flags().IntVar(&tlsClientCertDays, "days", 365, "Validity period in days")
+
+	tlsClientCertCmd.AddCommand(tlsClientCertCreateCmd)
+	tlsClientCertCmd.AddCommand(tlsClientCertListCmd)
+	tlsClientCertCmd.AddCommand(tlsClientCertRevokeCmd)
+
+	tlsCmd.AddCommand(tlsInitCmd)
+	tlsCmd.AddCommand(tlsTrustCmd)
+	tlsCmd.AddCommand(tlsClientCertCmd)
+}
+
+func runTLSInit(_ *cobra.Command, _ []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	dir := config.GetTLSDir()
+	certPath := filepath.Join(dir, certs.ServerCertFile)
+	keyPath := filepath.Join(dir, certs.ServerKeyFile)
+
+	if cfg.TLSEnabled() && !tlsInitForce {
+		ui.Info("TLS is already enabled (%s)", cfg.TLSCert)
+		ui.Info("Reissue the certificate with: lgh tls init --force")
+		return nil
+	}
+
+	ca, created, err := certs.InitCA(dir)
+	if err != nil {
+		return err
+	}
+	if created {
+		ui.Success("Created local CA: %s", certs.CAPath(dir))
+	} else {
+		ui.Info("Using existing CA: %s", certs.CAPath(dir))
+	}
+
+	hosts := append(certs.DefaultHosts(mdns.Hostname(), cfg.BindAddress), tlsInitHosts...)
+	if err := ca.IssueServerCert(certPath, keyPath, hosts); err != nil {
+		return err
+	}
+	ui.Success("Issued server certificate: %s", certPath)
+	ui.Info("Valid for: %s", strings.Join(hosts, ", "))
+
+	cfg.TLSCert = certPath
+	cfg.TLSKey = keyPath
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	ui.Success("TLS enabled")
+
+	updateRemoteSchemes(cfg)
+
+	fmt.Println()
+	ui.Info("Make git trust the CA:")
+	ui.Command("lgh tls trust")
+
+	if running, _ := server.IsRunning(); running {
+		ui.Info("Restart the server to apply: lgh stop && lgh serve -d")
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// updateRemoteSchemes switches the 'lgh' remote of registered repos to https
+func updateRemoteSchemes(cfg *config.Config) {
+	reg := registry.New()
+	repos, err := reg.List()
+	if err != nil {
+		return
+	}
+
+	updated := 0
+	for _, repo := range repos {
+		if repo.SourcePath == "" {
+			// Created by push, there is no local working copy
+			continue
+		}
+		remoteURL, err := git.GetRemoteURL(repo.SourcePath, "lgh")
+		if err != nil || !strings.HasPrefix(remoteURL, "http://") {
+			continue
+		}
+		newURL := cfg.Scheme() + "://" + strings.TrimPrefix(remoteURL, "http://")
+		if err := git.AddRemote(repo.SourcePath, "lgh", newURL); err != nil {
+			ui.Warning("Failed to update remote for '%s': %v", repo.Name, err)
+			continue
+		}
+		updated++
+	}
+
+	if updated > 0 {
+		ui.Success("Switched %d 'lgh' remote(s) to https", updated)
+	}
+}
+
+func runTLSTrust(_ *cobra.Command, _ []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	caPath := certs.CAPath(config.GetTLSDir())
+	if _, err := certs.LoadCA(config.GetTLSDir()); err != nil {
+		return fmt.Errorf("no local CA found. Run 'lgh tls init' first")
+	}
+
+	hostsSeen := map[string]bool{}
+	hosts := []string{}
+	for _, h := range []string{cfg.BindAddress, "localhost", mdns.Hostname() + ".local"} {
+		if h == "0.0.0.0" || h == "::" || hostsSeen[h] {
+			continue
+		}
+		hostsSeen[h] = true
+		hosts = append(hosts, h)
+	}
+
+	ui.Title("Trust the LGH CA")
+	fmt.Println()
+	ui.Info("On this machine, run:")
+	for _, h := range hosts {
+		ui.Command(fmt.Sprintf("git config --global http.\"https://%s:%d/\".sslCAInfo %s", h, cfg.Port, caPath))
+	}
+	fmt.Println()
+	ui.Info("On other machines, copy %s there and run the same command", caPath)
+	ui.Info("with the server's address and the copied file's path.")
+	fmt.Println()
+
+	if !cfg.TLSEnabled() {
+		ui.Warning("TLS is not enabled. Run 'lgh tls init' to enable it.")
+	}
+
+	return nil
+}
+
+func runTLSClientCertCreate(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	name := args[0]
+	if err := users.ValidateName(name); err != nil {
+		return err
+	}
+	if tlsClientCertDays <= 0 {
+		return fmt.Errorf("--days must be positive")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if !cfg.TLSEnabled() {
+		return fmt.Errorf("TLS is not enabled. Run 'lgh tls init' first")
+	}
+	if name != cfg.AuthUser {
+		if _, err := users.NewStore().Find(name); err != nil {
+			return fmt.Errorf("user '%s' not found (create it with 'lgh user add %s')", name, name)
+		}
+	}
+
+	dir := config.GetTLSDir()
+	ca, err := certs.LoadCA(dir)
+	if err != nil {
+		return fmt.Errorf("no local CA found. Run 'lgh tls init' first")
+	}
+
+	certPath, keyPath := certs.ClientCertPaths(dir, name)
+	cert, err := ca.IssueClientCert(certPath, keyPath, name, time.Duration(tlsClientCertDays)*24*time.Hour)
+	if err != nil {
+		return err
+	}
+	if err := certs.NewClientStore(dir).Add(name, cert); err != nil {
+		return err
+	}
+
+	ui.Success("Issued client certificate for '%s' (expires %s)", name, cert.NotAfter.Format("2006-01-02"))
+	if name == cfg.AuthUser {
+		ui.Warning("'%s' is the config.yaml account: this certificate has owner rights", name)
+	}
+	ui.Info("Certificate: %s", certPath)
+	ui.Info("Key:         %s", keyPath)
+
+	// Certificates are useless until the server asks for them
+	if !cfg.TLSClientAuth || !cfg.AuthEnabled {
+		cfg.TLSClientAuth = true
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		if !cfg.AuthEnabled {
+			cfg.AuthEnabled = true
+			if err := updateAuthConfig(cfg); err != nil {
+				return err
+			}
+		}
+		ui.Success("Client certificate authentication enabled")
+
+		if running, _ := server.IsRunning(); running {
+			ui.Info("Restart the server to apply: lgh stop && lgh serve -d")
+		}
+	}
+
+	fmt.Println()
+	ui.Info("Copy both files to the client machine and run:")
+	ui.Command(fmt.Sprintf("git config --global http.\"https://<host>:%d/\".sslCert <path>/%s", cfg.Port, filepath.Base(certPath)))
+	ui.Command(fmt.Sprintf("git config --global http.\"https://<host>:%d/\".sslKey <path>/%s", cfg.Port, filepath.Base(keyPath)))
+	ui.Info("The client must also trust the CA (see 'lgh tls trust').")
+	fmt.Println()
+
+	return nil
+}
+
+func runTLSClientCertList(_ *cobra.Command, _ []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	list, err := certs.NewClientStore(config.GetTLSDir()).List()
+	if err != nil {
+		return fmt.Errorf("failed to list client certificates: %w", err)
+	}
+
+	if len(list) == 0 {
+		ui.Info("No client certificates issued yet.")
+		fmt.Println()
+		ui.Info("Issue one:")
+		ui.Command("lgh tls client-cert create <name>")
+		return nil
+	}
+
+	ui.Title("Client Certificates (%d)", len(list))
+
+	table := ui.NewTable([]string{"Name", "Serial", "Status", "Created", "Expires"})
+	for _, c := range list {
+		status := ui.Green("active")
+		if c.Revoked {
+			status = ui.Red("revoked")
+		} else if time.Now().After(c.ExpiresAt) {
+			status = ui.Yellow("expired")
+		}
+		serial := c.Serial
+		if len(serial) > 12 {
+			serial = serial[:12]
+		}
+		table.AddRow([]string{
+			ui.Bold(c.Name),
+			ui.Gray(serial),
+			status,
+			ui.Gray(c.CreatedAt.Format("2006-01-02 15:04")),
+			c.ExpiresAt.Format("2006-01-02"),
+		})
+	}
+
+	table.Render()
+	fmt.Println()
+
+	return nil
+}
+
+func runTLSClientCertRevoke(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	name := args[0]
+	count, err := certs.NewClientStore(config.GetTLSDir()).Revoke(name)
+	if err != nil {
+		return err
+	}
+
+	ui.Success("Revoked %d client certificate(s) for '%s'", count, name)
+	return nil
+}
@@ -0,0 +1,245 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/server"
+	"github.com/JoeGlenn1213/lgh/internal/tokens"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+var (
+	tokenScope   string
+	tokenRepo    string
+	tokenExpires string
+	tokenName    string
+	tokenUser    string
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage access tokens",
+	Long: `Manage scoped personal access tokens.
+
+Tokens are meant for CI scripts and agents: they can be limited to a
+scope and a single repository, they expire, and they can be revoked
+without rotating any password. Only a hash of each token is stored.
+
+Scopes:
+  read    clone, fetch and the read-only /api endpoints
+  write   read + push
+  admin   write + commit statuses and other /api writes
+
+Use a token as the password in a clone URL, or as a Bearer header:
+  git clone http://ci:<token>@<host>:<port>/repo.git
+  curl -H "Authorization: Bearer <token>" http://<host>:<port>/api/...`,
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an access token",
+	Long: `Create an access token. The token is printed once and cannot be shown again.
+
+Examples:
+  lgh token create --scope read
+  lgh token create --scope write --repo my-app --expires 30d --name ci
+  lgh token create --scope admin --user alice --expires never`,
+	Args: cobra.NoArgs,
+	RunE: runTokenCreate,
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List access tokens",
+	Aliases: []string{"ls"},
+	RunE:    runTokenList,
+}
+
+var tokenRevokeCmd = &cobra.Command{
+	Use:     "revoke <id>",
+	Short:   "Revoke an access token",
+	Aliases: []string{"rm"},
+	Args:    cobra.ExactArgs(1),
+	RunE:    runTokenRevoke,
+}
+
+func init() {
+	tokenCreateCmd.Flags().StringVar(&tokenScope, "scope", "read", "Token scope: read, write or admin")
+	tokenCreateCmd.Flags().StringVar(&tokenRepo, "repo", "", "Restrict the token to a single repository")
+	tokenCreateCmd.Flags().StringVar(&tokenExpires, "expires", "90d", "Lifetime such as 30d or 12h, or 'never'")
+	tokenCreateCmd.Flags().StringVar(&tokenName, "name", "", "Description of the token (e.g. ci)")
+	tokenCreateCmd.Flags().StringVar(&tokenUser, "user", "", "User the token acts as (default: token-<id>)")
+
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCmd.AddCommand(tokenListCmd)
+	tokenCmd.AddCommand(tokenRevokeCmd)
+}
+
+func runTokenCreate(_ *cobra.Command, _ []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	scope, err := tokens.ParseScope(tokenScope)
+	if err != nil {
+		return err
+	}
+
+	lifetime, err := tokens.ParseExpiry(tokenExpires)
+	if err != nil {
+		return err
+	}
+
+	if tokenRepo != "" {
+		reg := registry.New()
+		if !reg.Exists(tokenRepo) {
+			return fmt.Errorf("repository '%s' not found", tokenRepo)
+		}
+	}
+
+	if tokenUser != "" && tokenUser != config.Get().AuthUser {
+		if err := users.ValidateName(tokenUser); err != nil {
+			return err
+		}
+		// Tokens of removed users stop working, so they must exist to begin with
+		if _, err := users.NewStore().Find(tokenUser); err != nil {
+			return fmt.Errorf("user '%s' not found (create it with 'lgh user add %s')", tokenUser, tokenUser)
+		}
+	}
+
+	id, err := server.GenerateToken(4)
+	if err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+	secret, err := server.GenerateToken(20)
+	if err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	tok := tokens.Token{
+		ID:         id,
+		Name:       tokenName,
+		User:       tokenUser,
+		Scope:      scope,
+		Repo:       tokenRepo,
+		SecretHash: tokens.HashSecret(secret),
+		CreatedAt:  time.Now(),
+	}
+	if lifetime > 0 {
+		tok.ExpiresAt = tok.CreatedAt.Add(lifetime)
+	}
+
+	store := tokens.NewStore()
+	if err := store.Add(tok); err != nil {
+		return err
+	}
+
+	ui.Success("Token created (id: %s, scope: %s)", id, scope)
+	fmt.Println()
+	fmt.Println(tokens.Format(id, secret))
+	fmt.Println()
+	ui.Warning("Copy this token now. It will not be shown again.")
+
+	cfg := config.Get()
+	if !cfg.AuthEnabled {
+		fmt.Println()
+		ui.Warning("Authentication is disabled, so tokens are not checked yet.")
+		ui.Info("Enable it with:")
+		ui.Command("lgh auth setup")
+	} else if running, _ := server.IsRunning(); running {
+		if n, _ := store.Count(); n == 1 {
+			// The server only enables token auth if tokens existed at startup
+			ui.Info("Restart the server to apply: lgh stop && lgh serve -d")
+		}
+	}
+	fmt.Println()
+
+	return nil
+}
+
+func runTokenList(_ *cobra.Command, _ []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	list, err := tokens.NewStore().List()
+	if err != nil {
+		return fmt.Errorf("failed to list tokens: %w", err)
+	}
+
+	if len(list) == 0 {
+		ui.Info("No tokens created yet.")
+		fmt.Println()
+		ui.Info("Create one:")
+		ui.Command("lgh token create --scope read")
+		return nil
+	}
+
+	ui.Title("Access Tokens (%d)", len(list))
+
+	table := ui.NewTable([]string{"ID", "Name", "User", "Scope", "Repo", "Expires"})
+	for _, t := range list {
+		user := t.User
+		if user == "" {
+			user = ui.Gray("token-" + t.ID)
+		}
+		repo := t.Repo
+		if repo == "" {
+			repo = ui.Gray("all")
+		}
+
+		expires := ui.Gray("never")
+		if t.Expired() {
+			expires = ui.Red("expired")
+		} else if !t.ExpiresAt.IsZero() {
+			expires = t.ExpiresAt.Format("2006-01-02 15:04")
+		}
+
+		table.AddRow([]string{ui.Bold(t.ID), t.Name, user, string(t.Scope), repo, expires})
+	}
+
+	table.Render()
+	fmt.Println()
+
+	return nil
+}
+
+func runTokenRevoke(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	id := args[0]
+	if err := tokens.NewStore().Revoke(id); err != nil {
+		return err
+	}
+
+	ui.Success("Token '%s' revoked", id)
+	return nil
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/event"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/trash"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+var (
+	trashRestoreAs string
+	trashPurgeAll  bool
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List, restore and purge removed repositories",
+	Long: `List, restore and purge repositories removed with 'lgh remove'.
+
+Removed repositories are kept in <repos_dir>/.trash together with their
+registry entry, commit statuses and LFS objects, and are purged
+automatically after trash_retention_days (default 30, 0 = never).
+The trash sits inside repos_dir rather than the data directory so that
+moving a repository there is a rename on the same filesystem.`,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List trashed repositories",
+	Args:    cobra.NoArgs,
+	RunE:    runTrashList,
+}
+
+// lgh trash restore <name> [--as <new-name>]
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Restore a trashed repository",
+	Long: `Restore a trashed repository under its old name or a new one.
+
+If the name was removed more than once, the most recent removal is restored.
+
+Examples:
+  lgh trash restore my-app
+  lgh trash restore team/api --as team/api-old   # the name is taken again`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTrashRestore,
+}
+
+// lgh trash purge [name] [--all]
+var trashPurgeCmd = &cobra.Command{
+	Use:   "purge [name]",
+	Short: "Delete trashed repositories for good",
+	Long: `Delete trashed repositories for good.
+
+Without arguments, purges the repositories past trash_retention_days.
+
+Examples:
+  lgh trash purge            # purge expired repositories
+  lgh trash purge my-app     # purge every trashed copy of my-app
+  lgh trash purge --all      # empty the trash`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTrashPurge,
+}
+
+func init() {
+	trashRestoreCmd.Flags().StringVar(&trashRestoreAs, "as", "", "Restore under a different name")
+	trashPurgeCmd.Flags().BoolVar(&trashPurgeAll, "all", false, "Purge every trashed repository")
+
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashPurgeCmd)
+}
+
+// purgeExpiredTrash purges repositories past the retention period, reporting
+// rather than failing: it runs as a side effect of other commands
+func purgeExpiredTrash(reg *registry.Registry, cfg *config.Config) {
+	purged, err := trash.PurgeExpired(reg, cfg)
+	for _, t := range purged {
+		ui.Info("Purged '%s' from the trash (removed %s)", t.Name, t.DeletedAt.Format("2006-01-02"))
+	}
+	if err != nil {
+		ui.Warning("Failed to purge trash: %v", err)
+	}
+}
+
+func runTrashList(_ *cobra.Command, _ []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	reg := registry.New()
+	cfg := config.Get()
+	purgeExpiredTrash(reg, cfg)
+
+	list, err := reg.TrashList()
+	if err != nil {
+		return err
+	}
+
+	ui.Title("Trash (%d)", len(list))
+	if len(list) == 0 {
+		ui.Info("The trash is empty.")
+		return nil
+	}
+
+	table := ui.NewTable([]string{"Name", "Removed", "Purged", "Source Path"})
+	for _, t := range list {
+		purge := "never"
+		if cfg.TrashRetentionDays > 0 {
+			purge = t.DeletedAt.Add(time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour).Format("2006-01-02")
+		}
+		source := t.SourcePath
+		if source == "" {
+			source = "-"
+		}
+		table.AddRow([]string{ui.Bold(t.Name), t.DeletedAt.Format("2006-01-02 15:04"), purge, source})
+	}
+	table.Render()
+	fmt.Println()
+	ui.Info("Restore with: lgh trash restore <name>")
+
+	return nil
+}
+
+func runTrashRestore(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	reg := registry.New()
+	cfg := config.Get()
+	trashed, err := trash.Find(reg, args[0])
+	if err != nil {
+		return err
+	}
+
+	name := trashed.Name
+	if trashRestoreAs != "" {
+		name = strings.TrimSuffix(strings.Trim(trashRestoreAs, "/"), ".git")
+	}
+	if reg.Exists(name) {
+		return fmt.Errorf("repository '%s' already exists, restore it under another name with --as", name)
+	}
+
+	ui.Title("Restore Repository: %s", name)
+	repo, err := trash.Restore(reg, cfg, trashed, name)
+	if err != nil {
+		return err
+	}
+	ui.Success("Restored %s", repo.BarePath)
+
+	// Give the source repository its remote back
+	remoteURL := cfg.BaseURL(cfg.BindAddress) + registry.URLPath(name)
+	if _, err := os.Stat(repo.SourcePath); repo.SourcePath != "" && err == nil {
+		if err := git.AddRemote(repo.SourcePath, "lgh", remoteURL); err != nil {
+			ui.Warning("Failed to add remote: %v", err)
+			ui.Info("You can add it manually: git remote add lgh %s", remoteURL)
+		} else {
+			ui.Success("Added remote 'lgh' -> %s", remoteURL)
+		}
+	}
+
+	event.Publish(event.RepoAdded, name, map[string]interface{}{
+		"source": repo.SourcePath,
+		"bare":   repo.BarePath,
+		"url":    remoteURL,
+		"via":    "restore",
+	})
+
+	fmt.Println()
+	ui.Success("Repository '%s' restored", name)
+	return nil
+}
+
+func runTrashPurge(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	reg := registry.New()
+	cfg := config.Get()
+	if len(args) == 0 && !trashPurgeAll {
+		purged, err := trash.PurgeExpired(reg, cfg)
+		ui.Success("Purged %d expired repositories", len(purged))
+		return err
+	}
+
+	list, err := reg.TrashList()
+	if err != nil {
+		return err
+	}
+	purged := 0
+	for i := range list {
+		if len(args) == 1 && list[i].Name != args[0] {
+			continue
+		}
+		if err := trash.Purge(reg, cfg, &list[i]); err != nil {
+			return err
+		}
+		ui.Success("Purged %s", list[i].TrashPath)
+		purged++
+	}
+	if len(args) == 1 && purged == 0 {
+		return fmt.Errorf("repository '%s' not found in trash", args[0])
+	}
+	ui.Success("Purged %d repositories", purged)
+	return nil
+}
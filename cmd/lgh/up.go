@@ -32,6 +32,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
 	"github.com/JoeGlenn1213/lgh/internal/ignore"
 	"github.com/JoeGlenn1213/lgh/internal/registry"
@@ -48,6 +49,9 @@ var upCmd = &cobra.Command{
   3. Commits with the provided message
   4. Pushes to LGH
 
+Sensitive files and dependency directories stop the push. Large files do
+not: 'up' suggests 'git lfs track' for them and offers to run it.
+
 This is the fastest way to backup your code to LGH.`,
 	Example: `  # Quick backup with commit message
   lgh up "完成鉴权模块"
@@ -138,10 +142,15 @@ func runUp(_ *cobra.Command, args []string) {
 			ui.Warning("Trash detection failed: %v", err)
 		} else if len(report.Items) > 0 {
 			printTrashReport(report)
-			if report.HasBlocking {
+			hasLargeFiles := suggestLFS(report)
+			if report.BlocksBesidesLargeFiles() {
 				ui.Error("Blocking issues found. Fix them or use --force to override.")
 				os.Exit(1)
 			}
+			// Large files alone do not stop the push
+			if hasLargeFiles {
+				offerLFSTrack(cwd, ignore.LFSTrackPatterns(report))
+			}
 		}
 	}
 
@@ -208,6 +217,54 @@ func printTrashReport(report *ignore.TrashReport) {
 	ui.Info("Total size: %s", ignore.FormatHumanSize(report.TotalSize))
 }
 
+// suggestLFS prints the "git lfs track" commands for the large files of a
+// report. LGH serves LFS objects itself, so no other setup is needed.
+// It returns false if the report has no large files.
+func suggestLFS(report *ignore.TrashReport) bool {
+	patterns := ignore.LFSTrackPatterns(report)
+	if len(patterns) == 0 {
+		return false
+	}
+
+	ui.Info("💡 Version large files with Git LFS (served by LGH):")
+	if err := exec.Command("git", "lfs", "version").Run(); err != nil {
+		fmt.Println("  # Install Git LFS first: https://git-lfs.com")
+	}
+	fmt.Println("  git lfs install")
+	for _, pattern := range patterns {
+		fmt.Printf("  git lfs track %q\n", pattern)
+	}
+	fmt.Println("  git add .gitattributes")
+	fmt.Println()
+	return true
+}
+
+// offerLFSTrack asks whether to run the suggested "git lfs track" commands
+// before the changes are staged. Without a terminal, or if declined, the
+// large files are pushed as regular git objects.
+func offerLFSTrack(dir string, patterns []string) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		ui.Warning("Pushing large files without Git LFS")
+		return
+	}
+	fmt.Print("Track them with Git LFS now? [y/N]: ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+		ui.Warning("Pushing large files without Git LFS")
+		return
+	}
+
+	if err := runGitCommand(dir, "lfs", "install", "--local"); err != nil {
+		ui.Warning("Failed to set up Git LFS: %v", err)
+		return
+	}
+	if err := runGitCommand(dir, append([]string{"lfs", "track"}, patterns...)...); err != nil {
+		ui.Warning("Failed to track large files with Git LFS: %v", err)
+		return
+	}
+	ui.Success("Large files are tracked with Git LFS")
+}
+
 // runGitCommandWithBufferHint runs a git command and provides helpful hints if it fails due to buffer issues
 func runGitCommandWithBufferHint(dir string, args ...string) error {
 	cmd := exec.Command("git", args...)
@@ -0,0 +1,295 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/server"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage user accounts",
+	Long: `Manage LGH user accounts.
+
+Each user has their own password, so a team can share one LGH server
+without sharing credentials. Pushes are recorded with the username.
+Accounts are stored in ~/.localgithub/users.yaml.
+
+Subcommands:
+  lgh user add <name>      Create a user (prompts for password)
+  lgh user remove <name>   Delete a user
+  lgh user list            List all users
+  lgh user passwd <name>   Change a user's password
+  lgh user groups <name>   Set a user's groups`,
+}
+
+var userAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Create a user account",
+	Long: `Create a user account and enable authentication if it is not enabled yet.
+
+Prompts for the password with hidden input.
+Password must be at least 8 characters.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUserAdd,
+}
+
+var userRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Short:   "Delete a user account",
+	Aliases: []string{"rm"},
+	Args:    cobra.ExactArgs(1),
+	RunE:    runUserRemove,
+}
+
+var userListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List user accounts",
+	Aliases: []string{"ls"},
+	RunE:    runUserList,
+}
+
+var userPasswdCmd = &cobra.Command{
+	Use:   "passwd <name>",
+	Short: "Change a user's password",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runUserPasswd,
+}
+
+var userGroupsCmd = &cobra.Command{
+	Use:   "groups <name> [group...]",
+	Short: "Set a user's groups",
+	Long: `Set the groups a user belongs to. Groups can be granted repository
+access with 'lgh repo grant <repo> --group <group>'.
+
+Run without groups to remove the user from all groups.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runUserGroups,
+}
+
+var userAddGroups []string
+
+func init() {
+	userAddCmd.Flags().StringSliceVar(&userAddGroups, "group", nil, "Groups the user belongs to (repeatable)")
+
+	userCmd.AddCommand(userAddCmd)
+	userCmd.AddCommand(userRemoveCmd)
+	userCmd.AddCommand(userListCmd)
+	userCmd.AddCommand(userPasswdCmd)
+	userCmd.AddCommand(userGroupsCmd)
+}
+
+// promptNewPassword asks for a password twice and returns its hash
+func promptNewPassword() (string, error) {
+	password, err := readPassword("Enter password: ")
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+
+	if vErr := validatePassword(password); vErr != nil {
+		return "", vErr
+	}
+
+	confirm, err := readPassword("Confirm password: ")
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+
+	if password != confirm {
+		return "", fmt.Errorf("passwords do not match")
+	}
+
+	hash, err := server.HashPassword(password)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return hash, nil
+}
+
+func runUserAdd(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	name := args[0]
+	if err := users.ValidateNewName(name, config.Get().AuthUser); err != nil {
+		return err
+	}
+
+	store := users.NewStore()
+	if _, err := store.Find(name); err == nil {
+		return fmt.Errorf("user '%s' already exists. Use 'lgh user passwd %s' to change the password", name, name)
+	}
+
+	ui.Title("Add User: %s", name)
+	fmt.Println()
+
+	hash, err := promptNewPassword()
+	if err != nil {
+		return err
+	}
+
+	if err := store.Add(name, hash); err != nil {
+		return err
+	}
+	if len(userAddGroups) > 0 {
+		if err := store.SetGroups(name, userAddGroups); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	ui.Success("User '%s' created", name)
+
+	// Accounts are useless while auth is off, so turn it on
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if !cfg.AuthEnabled {
+		cfg.AuthEnabled = true
+		if err := updateAuthConfig(cfg); err != nil {
+			return err
+		}
+		ui.Success("Authentication enabled")
+
+		if running, _ := server.IsRunning(); running {
+			ui.Info("Restart the server to apply: lgh stop && lgh serve -d")
+		}
+	}
+	fmt.Println()
+
+	return nil
+}
+
+func runUserRemove(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	name := args[0]
+	store := users.NewStore()
+	if err := store.Remove(name); err != nil {
+		return err
+	}
+
+	ui.Success("User '%s' removed", name)
+	return nil
+}
+
+func runUserList(_ *cobra.Command, _ []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	store := users.NewStore()
+	list, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	cfg := config.Get()
+
+	if len(list) == 0 && cfg.AuthUser == "" {
+		ui.Info("No users configured yet.")
+		fmt.Println()
+		ui.Info("Add a user:")
+		ui.Command("lgh user add <name>")
+		return nil
+	}
+
+	ui.Title("Users (%d)", len(list))
+
+	table := ui.NewTable([]string{"Name", "Source", "Groups", "Created"})
+	if cfg.AuthUser != "" {
+		table.AddRow([]string{ui.Bold(cfg.AuthUser), "config.yaml", ui.Gray("-"), ui.Gray("-")})
+	}
+	for _, u := range list {
+		groups := strings.Join(u.Groups, ", ")
+		if groups == "" {
+			groups = ui.Gray("-")
+		}
+		table.AddRow([]string{
+			ui.Bold(u.Name),
+			"users.yaml",
+			groups,
+			ui.Gray(u.CreatedAt.Format("2006-01-02 15:04")),
+		})
+	}
+
+	table.Render()
+	fmt.Println()
+
+	return nil
+}
+
+func runUserPasswd(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	name := args[0]
+	store := users.NewStore()
+	if _, err := store.Find(name); err != nil {
+		return err
+	}
+
+	hash, err := promptNewPassword()
+	if err != nil {
+		return err
+	}
+
+	if err := store.SetPassword(name, hash); err != nil {
+		return err
+	}
+
+	ui.Success("Password updated for '%s'", name)
+	return nil
+}
+
+func runUserGroups(_ *cobra.Command, args []string) error {
+	if err := ensureInitialized(); err != nil {
+		return err
+	}
+
+	name := args[0]
+	groups := args[1:]
+
+	store := users.NewStore()
+	if err := store.SetGroups(name, groups); err != nil {
+		return err
+	}
+
+	if len(groups) == 0 {
+		ui.Success("Removed '%s' from all groups", name)
+	} else {
+		ui.Success("'%s' is now in: %s", name, strings.Join(groups, ", "))
+	}
+	return nil
+}
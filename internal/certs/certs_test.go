@@ -0,0 +1,137 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInitCAIsIdempotent(t *testing.T) {
+	dir := t.TempDir()
+
+	ca, created, err := InitCA(dir)
+	if err != nil {
+		t.Fatalf("InitCA failed: %v", err)
+	}
+	if !created || !ca.Cert.IsCA {
+		t.Fatalf("expected a new CA certificate, created=%v", created)
+	}
+
+	again, created, err := InitCA(dir)
+	if err != nil {
+		t.Fatalf("second InitCA failed: %v", err)
+	}
+	if created {
+		t.Error("second InitCA should reuse the existing CA")
+	}
+	if again.Cert.SerialNumber.Cmp(ca.Cert.SerialNumber) != 0 {
+		t.Error("second InitCA returned a different CA")
+	}
+
+	info, err := os.Stat(filepath.Join(dir, CAKeyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("CA key mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestIssueServerCert(t *testing.T) {
+	dir := t.TempDir()
+	ca, _, err := InitCA(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPath := filepath.Join(dir, ServerCertFile)
+	keyPath := filepath.Join(dir, ServerKeyFile)
+	if err := ca.IssueServerCert(certPath, keyPath, []string{"localhost", "192.168.1.20", "box.local"}); err != nil {
+		t.Fatalf("IssueServerCert failed: %v", err)
+	}
+
+	pair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		t.Fatalf("failed to load key pair: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	for _, host := range []string{"localhost", "192.168.1.20", "box.local"} {
+		if _, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: roots}); err != nil {
+			t.Errorf("certificate not valid for %s: %v", host, err)
+		}
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{DNSName: "other.local", Roots: roots}); err == nil {
+		t.Error("certificate should not be valid for other.local")
+	}
+}
+
+func TestClientStoreAllowedAndRevoke(t *testing.T) {
+	dir := t.TempDir()
+	ca, _, err := InitCA(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPath, keyPath := ClientCertPaths(dir, "alice")
+	cert, err := ca.IssueClientCert(certPath, keyPath, "alice", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("IssueClientCert failed: %v", err)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:     ca.CertPool(),
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		t.Fatalf("client certificate does not verify: %v", err)
+	}
+
+	store := NewClientStore(dir)
+	if store.Allowed(cert) {
+		t.Error("unrecorded certificate should not be allowed")
+	}
+	if err := store.Add("alice", cert); err != nil {
+		t.Fatal(err)
+	}
+	if !store.Allowed(cert) {
+		t.Error("recorded certificate should be allowed")
+	}
+
+	count, err := store.Revoke("alice")
+	if err != nil || count != 1 {
+		t.Fatalf("Revoke = %d, %v; want 1, nil", count, err)
+	}
+	if store.Allowed(cert) {
+		t.Error("revoked certificate should not be allowed")
+	}
+	if _, err := store.Revoke("alice"); err == nil {
+		t.Error("revoking again should fail")
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package certs
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+// clientsFile records issued client certificates in the TLS directory
+const clientsFile = "clients.yaml"
+
+// ClientCert is the record of an issued client certificate
+type ClientCert struct {
+	Name      string    `yaml:"name"`
+	Serial    string    `yaml:"serial"`
+	CreatedAt time.Time `yaml:"created_at"`
+	ExpiresAt time.Time `yaml:"expires_at"`
+	Revoked   bool      `yaml:"revoked,omitempty"`
+}
+
+// ClientCerts holds all client certificate records
+type ClientCerts struct {
+	Certs []ClientCert `yaml:"client_certs"`
+}
+
+// ClientStore manages the client certificate records.
+// Only certificates recorded here and not revoked are accepted by the server.
+type ClientStore struct {
+	path string
+	mu   sync.RWMutex
+}
+
+// NewClientStore creates a ClientStore for the TLS directory dir
+func NewClientStore(dir string) *ClientStore {
+	return &ClientStore{
+		path: filepath.Join(dir, clientsFile),
+	}
+}
+
+// ClientCertPaths returns the certificate and key paths for a client name
+func ClientCertPaths(dir, name string) (certPath, keyPath string) {
+	base := filepath.Join(dir, ClientsDir, name)
+	return base + ".pem", base + "-key.pem"
+}
+
+// load reads the clients file with file locking
+func (s *ClientStore) load() (*ClientCerts, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fileLock := registry.NewFileLock(s.path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fileLock.Unlock()
+
+	certs := &ClientCerts{Certs: []ClientCert{}}
+
+	// nolint:gosec // G304: path is internally constructed and trusted
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return certs, nil
+		}
+		return nil, fmt.Errorf("failed to read client certificates file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, certs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal client certificates: %w", err)
+	}
+
+	return certs, nil
+}
+
+// save writes the clients file with file locking
+func (s *ClientStore) save(certs *ClientCerts) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fileLock := registry.NewFileLock(s.path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fileLock.Unlock()
+
+	data, err := yaml.Marshal(certs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal client certificates: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write client certificates file: %w", err)
+	}
+
+	return nil
+}
+
+// Add records a newly issued client certificate
+func (s *ClientStore) Add(name string, cert *x509.Certificate) error {
+	certs, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	certs.Certs = append(certs.Certs, ClientCert{
+		Name:      name,
+		Serial:    cert.SerialNumber.Text(16),
+		CreatedAt: time.Now(),
+		ExpiresAt: cert.NotAfter,
+	})
+
+	return s.save(certs)
+}
+
+// Revoke revokes every certificate issued to name and returns how many were revoked
+func (s *ClientStore) Revoke(name string) (int, error) {
+	certs, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for i := range certs.Certs {
+		if certs.Certs[i].Name == name && !certs.Certs[i].Revoked {
+			certs.Certs[i].Revoked = true
+			revoked++
+		}
+	}
+
+	if revoked == 0 {
+		return 0, fmt.Errorf("no active client certificate for '%s'", name)
+	}
+	return revoked, s.save(certs)
+}
+
+// List returns all client certificate records
+func (s *ClientStore) List() ([]ClientCert, error) {
+	certs, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return certs.Certs, nil
+}
+
+// Allowed reports whether cert was issued by LGH to its CommonName
+// and has not been revoked.
+func (s *ClientStore) Allowed(cert *x509.Certificate) bool {
+	certs, err := s.load()
+	if err != nil {
+		return false
+	}
+
+	serial := cert.SerialNumber.Text(16)
+	for _, c := range certs.Certs {
+		if c.Serial == serial {
+			return c.Name == cert.Subject.CommonName && !c.Revoked
+		}
+	}
+	return false
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sync"
 
 	"github.com/spf13/viper"
@@ -39,8 +40,17 @@ const (
 	ConfigFileName = "config"
 	// ConfigFileType is the type of the config file
 	ConfigFileType = "yaml"
+	// DefaultGitQueueSize is how many git requests may wait for a free process slot
+	DefaultGitQueueSize = 32
+	// DefaultRenameRedirectDays is how long the old URL of a renamed repository redirects
+	DefaultRenameRedirectDays = 30
+	// DefaultTrashRetentionDays is how long removed repositories can be restored
+	DefaultTrashRetentionDays = 30
 )
 
+// DefaultMaxGitProcesses is the default number of concurrent git processes
+var DefaultMaxGitProcesses = runtime.NumCPU()
+
 var (
 	once     sync.Once
 	instance *Config
@@ -58,6 +68,50 @@ type Config struct {
 	AuthEnabled      bool   `mapstructure:"auth_enabled"`
 	AuthUser         string `mapstructure:"auth_user"`
 	AuthPasswordHash string `mapstructure:"auth_password_hash"`
+	// TLS (optional): serve HTTPS when both are set
+	TLSCert string `mapstructure:"tls_cert"`
+	TLSKey  string `mapstructure:"tls_key"`
+	// TLSClientAuth accepts client certificates issued by the local CA;
+	// the certificate CommonName becomes the authenticated user
+	TLSClientAuth bool `mapstructure:"tls_client_auth"`
+	// Concurrency limits for git upload-pack/receive-pack processes
+	MaxGitProcesses int `mapstructure:"max_git_processes"`
+	GitQueueSize    int `mapstructure:"git_queue_size"`
+	// GitDaemonPort enables the read-only git:// listener (0 = disabled)
+	GitDaemonPort int `mapstructure:"git_daemon_port"`
+	// SSHPort enables the embedded SSH server for git over ssh:// (0 = disabled)
+	SSHPort int `mapstructure:"ssh_port"`
+	// RenameRedirectDays keeps old URLs of renamed repositories redirecting (0 = no redirect)
+	RenameRedirectDays int `mapstructure:"rename_redirect_days"`
+	// TrashRetentionDays is how long removed repositories stay in the trash (0 = until purged by hand)
+	TrashRetentionDays int `mapstructure:"trash_retention_days"`
+	// TrustedProxies lists the proxy addresses (IPs or CIDRs) whose
+	// X-Forwarded-For header names the real client, e.g. a local ngrok agent
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+// TLSEnabled reports whether the server is configured to serve HTTPS
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCert != "" && c.TLSKey != ""
+}
+
+// ClientCertAuthEnabled reports whether verified client certificates are
+// accepted as identities (requires TLS)
+func (c *Config) ClientCertAuthEnabled() bool {
+	return c.TLSEnabled() && c.TLSClientAuth
+}
+
+// Scheme returns "https" when TLS is enabled, "http" otherwise
+func (c *Config) Scheme() string {
+	if c.TLSEnabled() {
+		return "https"
+	}
+	return "http"
+}
+
+// BaseURL returns the server URL for the given host, e.g. "https://127.0.0.1:9418"
+func (c *Config) BaseURL(host string) string {
+	return fmt.Sprintf("%s://%s:%d", c.Scheme(), host, c.Port)
 }
 
 // GetLGHDir returns the LGH data directory path
@@ -84,6 +138,26 @@ func GetMappingsPath() string {
 	return filepath.Join(GetLGHDir(), "mappings.yaml")
 }
 
+// GetUsersPath returns the user accounts file path
+func GetUsersPath() string {
+	return filepath.Join(GetLGHDir(), "users.yaml")
+}
+
+// GetTokensPath returns the access tokens file path
+func GetTokensPath() string {
+	return filepath.Join(GetLGHDir(), "tokens.yaml")
+}
+
+// GetTLSDir returns the directory holding the local CA and certificates
+func GetTLSDir() string {
+	return filepath.Join(GetLGHDir(), "tls")
+}
+
+// GetSSHDir returns the directory holding the SSH host key and authorized keys
+func GetSSHDir() string {
+	return filepath.Join(GetLGHDir(), "ssh")
+}
+
 // GetPIDPath returns the PID file path
 func GetPIDPath() string {
 	return filepath.Join(GetLGHDir(), "lgh.pid")
@@ -94,12 +168,16 @@ func Load() (*Config, error) {
 	var err error
 	once.Do(func() {
 		instance = &Config{
-			Port:        DefaultPort,
-			BindAddress: DefaultBindAddress,
-			ReposDir:    GetReposDir(),
-			ReadOnly:    false,
-			MDNSEnabled: false,
-			DataDir:     GetLGHDir(),
+			Port:               DefaultPort,
+			BindAddress:        DefaultBindAddress,
+			ReposDir:           GetReposDir(),
+			ReadOnly:           false,
+			MDNSEnabled:        false,
+			DataDir:            GetLGHDir(),
+			MaxGitProcesses:    DefaultMaxGitProcesses,
+			GitQueueSize:       DefaultGitQueueSize,
+			RenameRedirectDays: DefaultRenameRedirectDays,
+			TrashRetentionDays: DefaultTrashRetentionDays,
 		}
 
 		viper.SetConfigName(ConfigFileName)
@@ -113,6 +191,10 @@ func Load() (*Config, error) {
 		viper.SetDefault("read_only", false)
 		viper.SetDefault("mdns_enabled", false)
 		viper.SetDefault("data_dir", GetLGHDir())
+		viper.SetDefault("max_git_processes", DefaultMaxGitProcesses)
+		viper.SetDefault("git_queue_size", DefaultGitQueueSize)
+		viper.SetDefault("rename_redirect_days", DefaultRenameRedirectDays)
+		viper.SetDefault("trash_retention_days", DefaultTrashRetentionDays)
 
 		if readErr := viper.ReadInConfig(); readErr != nil {
 			if _, ok := readErr.(viper.ConfigFileNotFoundError); !ok {
@@ -148,6 +230,32 @@ func Save(cfg *Config) error {
 	viper.Set("read_only", cfg.ReadOnly)
 	viper.Set("mdns_enabled", cfg.MDNSEnabled)
 	viper.Set("data_dir", cfg.DataDir)
+	viper.Set("tls_cert", cfg.TLSCert)
+	viper.Set("tls_key", cfg.TLSKey)
+	viper.Set("tls_client_auth", cfg.TLSClientAuth)
+	viper.Set("max_git_processes", cfg.MaxGitProcesses)
+	viper.Set("git_queue_size", cfg.GitQueueSize)
+	viper.Set("git_daemon_port", cfg.GitDaemonPort)
+	viper.Set("ssh_port", cfg.SSHPort)
+	viper.Set("rename_redirect_days", cfg.RenameRedirectDays)
+	viper.Set("trash_retention_days", cfg.TrashRetentionDays)
+	viper.Set("trusted_proxies", cfg.TrustedProxies)
+
+	configPath := GetConfigPath()
+	if err := viper.WriteConfigAs(configPath); err != nil {
+		return err
+	}
+	// SECURITY: Ensure config file is only readable by owner to protect password hash
+	return os.Chmod(configPath, 0600)
+}
+
+// SaveAuthPasswordHash replaces the stored password hash of the config account.
+// Used to migrate legacy hashes after a successful login.
+func SaveAuthPasswordHash(hash string) error {
+	viper.Set("auth_password_hash", hash)
+	if instance != nil {
+		instance.AuthPasswordHash = hash
+	}
 
 	configPath := GetConfigPath()
 	if err := viper.WriteConfigAs(configPath); err != nil {
@@ -160,12 +268,16 @@ func Save(cfg *Config) error {
 // CreateDefaultConfig creates a default configuration file
 func CreateDefaultConfig() error {
 	cfg := &Config{
-		Port:        DefaultPort,
-		BindAddress: DefaultBindAddress,
-		ReposDir:    GetReposDir(),
-		ReadOnly:    false,
-		MDNSEnabled: false,
-		DataDir:     GetLGHDir(),
+		Port:               DefaultPort,
+		BindAddress:        DefaultBindAddress,
+		ReposDir:           GetReposDir(),
+		ReadOnly:           false,
+		MDNSEnabled:        false,
+		DataDir:            GetLGHDir(),
+		MaxGitProcesses:    DefaultMaxGitProcesses,
+		GitQueueSize:       DefaultGitQueueSize,
+		RenameRedirectDays: DefaultRenameRedirectDays,
+		TrashRetentionDays: DefaultTrashRetentionDays,
 	}
 	return Save(cfg)
 }
@@ -158,6 +158,7 @@ func TestEventTypes(t *testing.T) {
 	}{
 		{RepoAdded, "repo.added"},
 		{RepoRemoved, "repo.removed"},
+		{RepoRenamed, "repo.renamed"},
 		{GitPush, "git.push"},
 		{GitTag, "git.tag"},
 	}
@@ -34,11 +34,18 @@ const (
 	RepoAdded Type = "repo.added"
 	// RepoRemoved indicates a repository was unregistered
 	RepoRemoved Type = "repo.removed"
+	// RepoRenamed indicates a repository was renamed or moved to another namespace
+	RepoRenamed Type = "repo.renamed"
 
 	// GitPush indicates a git push operation (receive-pack) occurred
 	GitPush Type = "git.push"
 	// GitTag indicates a tag was created/pushed
 	GitTag Type = "git.tag"
+	// GitPushRejected indicates a push was refused by a server-side check
+	GitPushRejected Type = "git.push.rejected"
+
+	// AuthFailed indicates a failed authentication attempt
+	AuthFailed Type = "auth.failed"
 )
 
 // Event represents a system event in LGH
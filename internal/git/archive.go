@@ -0,0 +1,57 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ArchiveFormats maps download suffixes to git archive formats
+var ArchiveFormats = map[string]string{
+	".tar.gz": "tar.gz",
+	".tgz":    "tar.gz",
+	".zip":    "zip",
+}
+
+// Archive writes an archive of commit to w, with every file under prefix
+// and, if paths are given, only those files and directories
+func Archive(ctx context.Context, repoPath, format, prefix, commit string, paths []string, w io.Writer) error {
+	args := []string{"-C", repoPath, "archive", "--format=" + format, "--prefix=" + prefix, commit}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+
+	// nolint:gosec // G204: format, commit and paths are validated by the caller
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_LITERAL_PATHSPECS=1")
+	cmd.Stdout = w
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git archive failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -22,29 +22,61 @@
 package git
 
 import (
+	"compress/gzip"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
-
-	// nolint:gosec // G504: net/http/cgi is required for Git backend and safe in modern Go.
-	"net/http/cgi"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/JoeGlenn1213/lgh/internal/config"
-	"github.com/JoeGlenn1213/lgh/internal/event"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+)
+
+// DefaultRemoteUser is reported to git as REMOTE_USER when the request is not authenticated
+const DefaultRemoteUser = "lgh-user"
+
+// PushIDEnv carries the push transaction ID to the managed hooks
+const PushIDEnv = "LGH_PUSH_ID"
+
+// Smart HTTP services, and upload-archive which git only speaks over
+// git:// and SSH
+const (
+	serviceUploadPack    = "git-upload-pack"
+	serviceReceivePack   = "git-receive-pack"
+	serviceUploadArchive = "git-upload-archive"
 )
 
-// Backend handles Git HTTP backend requests
+// gitProtocolPattern limits the Git-Protocol header to the characters git
+// uses for its key=value parameters (e.g. "version=2")
+var gitProtocolPattern = regexp.MustCompile(`^[A-Za-z0-9=:._-]+$`)
+
+// Backend serves the Git smart HTTP protocol by running upload-pack and
+// receive-pack in stateless-rpc mode
 type Backend struct {
-	reposDir        string
-	readOnly        bool
-	gitPath         string
-	httpBackendPath string
+	reposDir string
+	readOnly bool
+	gitPath  string
+	lghPath  string   // passed to managed hooks as LGH_BIN
+	limiter  *Limiter // nil means no concurrency limit
 }
 
+// retryAfterSeconds is suggested to clients rejected because the queue is full
+const retryAfterSeconds = "5"
+
+// processKillDelay bounds how long a cancelled git process may keep its
+// pipes open after being killed
+const processKillDelay = 5 * time.Second
+
 // NewBackend creates a new Git HTTP backend handler
 func NewBackend(reposDir string, readOnly bool) (*Backend, error) {
 	gitPath, err := CheckGitInstalled()
@@ -52,45 +84,26 @@ func NewBackend(reposDir string, readOnly bool) (*Backend, error) {
 		return nil, err
 	}
 
-	// Find git-http-backend
-	gitExecPath, err := getGitExecPath()
-	if err != nil {
-		return nil, err
-	}
-
-	httpBackendPath := filepath.Join(gitExecPath, "git-http-backend")
-	// On Windows, try with .exe extension if the base name doesn't exist
-	if _, err := os.Stat(httpBackendPath); os.IsNotExist(err) {
-		httpBackendPathExe := httpBackendPath + ".exe"
-		if _, errExe := os.Stat(httpBackendPathExe); errExe == nil {
-			httpBackendPath = httpBackendPathExe
-		} else {
-			return nil, fmt.Errorf("git-http-backend not found at %s or %s", httpBackendPath, httpBackendPathExe)
-		}
-	}
+	// Managed hooks call back into this binary; without it they do nothing
+	lghPath, _ := os.Executable()
 
 	return &Backend{
-		reposDir:        reposDir,
-		readOnly:        readOnly,
-		gitPath:         gitPath,
-		httpBackendPath: httpBackendPath,
+		reposDir: reposDir,
+		readOnly: readOnly,
+		gitPath:  gitPath,
+		lghPath:  lghPath,
 	}, nil
 }
 
-// getGitExecPath returns the git exec path
-func getGitExecPath() (string, error) {
-	cmd := exec.Command("git", "--exec-path")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to get git exec path: %w", err)
-	}
-	return strings.TrimSpace(string(output)), nil
+// SetLimiter caps concurrent git processes; requests over the limit queue
+func (b *Backend) SetLimiter(l *Limiter) {
+	b.limiter = l
 }
 
 // ServeHTTP implements http.Handler for Git HTTP backend
 func (b *Backend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Extract repository name from path
-	// URL format: /{repo}.git/info/refs or /{repo}.git/git-receive-pack etc.
+	// URL format: /{repo}.git/info/refs or /{namespace}/{repo}.git/git-receive-pack etc.
 	repoPath, gitPath := b.parseRequest(r)
 	if repoPath == "" {
 		http.Error(w, "Invalid repository path", http.StatusBadRequest)
@@ -104,7 +117,7 @@ func (b *Backend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Build the full path to the bare repository
-	fullRepoPath := filepath.Join(b.reposDir, repoPath)
+	fullRepoPath := filepath.Join(b.reposDir, filepath.FromSlash(repoPath))
 
 	// Verify repository exists
 	if !IsBareRepo(fullRepoPath) {
@@ -112,120 +125,229 @@ func (b *Backend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Setup CGI handler using pre-validated httpBackendPath
-	handler := &cgi.Handler{
-		Path: b.httpBackendPath,
-		Env: []string{
-			"GIT_PROJECT_ROOT=" + b.reposDir,
-			"GIT_HTTP_EXPORT_ALL=1",
-			"REMOTE_USER=lgh-user",
-		},
-	}
-
-	// The PATH_INFO needs to be set correctly
-	// We need to update the request path for CGI
-	originalPath := r.URL.Path
-	r.URL.Path = "/" + repoPath + gitPath
-
-	// Pre-push: capture refs
-	isPush := b.isPushRequest(r, gitPath)
-	var preRefs map[string]string
-	var preErr error
-	if isPush {
-		preRefs, preErr = GetRefs(fullRepoPath)
-	}
-
-	handler.ServeHTTP(w, r)
-
-	// Restore original path
-	r.URL.Path = originalPath
-
-	// Post-push: compare refs and emit event
-	if isPush && preErr == nil {
-		postRefs, err := GetRefs(fullRepoPath)
-		if err == nil {
-			changes := make(map[string]map[string]string)
-			zeroHash := "0000000000000000000000000000000000000000"
-
-			// Created or Updated
-			for ref, newHash := range postRefs {
-				if oldHash, exists := preRefs[ref]; !exists {
-					changes[ref] = map[string]string{"old": zeroHash, "new": newHash, "action": "created"}
-				} else if oldHash != newHash {
-					changes[ref] = map[string]string{"old": oldHash, "new": newHash, "action": "updated"}
-				}
+	if b.limiter != nil {
+		release, err := b.limiter.Acquire(r.Context())
+		if err != nil {
+			if errors.Is(err, ErrQueueFull) {
+				w.Header().Set("Retry-After", retryAfterSeconds)
+				http.Error(w, "Server busy: too many concurrent git operations, retry later", http.StatusServiceUnavailable)
 			}
+			// Otherwise the client went away while queued
+			return
+		}
+		defer release()
+	}
 
-			// Deleted
-			for ref, oldHash := range preRefs {
-				if _, exists := postRefs[ref]; !exists {
-					changes[ref] = map[string]string{"old": oldHash, "new": zeroHash, "action": "deleted"}
-				}
-			}
+	switch {
+	case gitPath == "/info/refs" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		service := r.URL.Query().Get("service")
+		if !isSmartService(service) {
+			// Dumb HTTP would expose the repository files directly
+			http.Error(w, "Only the smart HTTP protocol is supported", http.StatusForbidden)
+			return
+		}
+		b.advertiseRefs(w, r, fullRepoPath, service)
 
-			if len(changes) > 0 {
-				// Separate tag changes from branch changes
-				branchChanges := make(map[string]map[string]string)
-				tagChanges := make(map[string]map[string]string)
-
-				for ref, change := range changes {
-					if strings.HasPrefix(ref, "refs/tags/") {
-						tagChanges[ref] = change
-					} else {
-						branchChanges[ref] = change
-					}
-				}
-
-				// Emit branch push event
-				if len(branchChanges) > 0 {
-					// Calculate changed files for each updated ref
-					changedFiles := make(map[string][]string)
-					for ref, change := range branchChanges {
-						if change["action"] == "updated" || change["action"] == "created" {
-							files, err := GetChangedFiles(fullRepoPath, change["old"], change["new"])
-							if err == nil && len(files) > 0 {
-								changedFiles[ref] = files
-							}
-						}
-					}
-
-					payload := map[string]interface{}{
-						"changes": branchChanges,
-					}
-					if len(changedFiles) > 0 {
-						payload["changed_files"] = changedFiles
-					}
-
-					event.Publish(event.GitPush, repoPath, payload)
-				}
-
-				// Emit tag event
-				if len(tagChanges) > 0 {
-					event.Publish(event.GitTag, repoPath, map[string]interface{}{
-						"changes": tagChanges,
-					})
-				}
-			}
+	case isSmartService(strings.TrimPrefix(gitPath, "/")):
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		b.serviceRPC(w, r, fullRepoPath, strings.TrimPrefix(gitPath, "/"))
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// advertiseRefs answers GET /info/refs?service=... with the ref advertisement
+func (b *Backend) advertiseRefs(w http.ResponseWriter, r *http.Request, repoPath, service string) {
+	w.Header().Set("Content-Type", "application/x-"+service+"-advertisement")
+	setNoCache(w)
+
+	env := b.gitEnv(r, service)
+
+	// Protocol v0/v1 responses start with a service announcement;
+	// in v2 the capability advertisement comes first.
+	if !strings.Contains(gitProtocol(r), "version=2") {
+		if _, err := io.WriteString(w, pktLine("# service="+service+"\n")+"0000"); err != nil {
+			return
+		}
+	}
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	b.runService(w, r, nil, env, service, "--stateless-rpc", "--advertise-refs", repoPath)
+}
+
+// serviceRPC answers POST /git-upload-pack and /git-receive-pack
+func (b *Backend) serviceRPC(w http.ResponseWriter, r *http.Request, repoPath, service string) {
+	var body io.Reader = r.Body
+	switch r.Header.Get("Content-Encoding") {
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, "Invalid gzip request body", http.StatusBadRequest)
+			return
+		}
+		defer func() { _ = gz.Close() }()
+		body = gz
+	case "", "identity":
+	default:
+		http.Error(w, "Unsupported Content-Encoding", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	env := b.gitEnv(r, service)
+
+	// Each push gets a transaction ID; the post-receive hook reports it
+	// with the exact ref updates, which become the git.push event.
+	if service == serviceReceivePack {
+		env = append(env, PushIDEnv+"="+uuid.New().String())
+	}
+
+	w.Header().Set("Content-Type", "application/x-"+service+"-result")
+	setNoCache(w)
+
+	b.runService(w, r, body, env, service, "--stateless-rpc", repoPath)
+}
+
+// runService runs "git upload-pack" or "git receive-pack", streaming stdin
+// from the request and stdout to the response as it is produced. The process
+// is killed if the client disconnects.
+func (b *Backend) runService(w http.ResponseWriter, r *http.Request, stdin io.Reader, env []string, service string, args ...string) {
+	args = append([]string{strings.TrimPrefix(service, "git-")}, args...)
+	// nolint:gosec // G204: service is one of the two smart HTTP services, repo path is validated
+	cmd := exec.CommandContext(r.Context(), b.gitPath, args...)
+	cmd.WaitDelay = processKillDelay
+	cmd.Env = env
+	cmd.Stdin = stdin
+	cmd.Stdout = &flushWriter{w: w, rc: http.NewResponseController(w)}
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if r.Context().Err() != nil {
+			fmt.Fprintf(os.Stderr, "git %s cancelled: client disconnected\n", args[0])
+			return
+		}
+		// Headers are usually sent by now; the client sees a truncated
+		// response and reports the error.
+		fmt.Fprintf(os.Stderr, "git %s failed: %v\n", args[0], err)
+	}
+}
+
+// gitEnv builds the environment for upload-pack and receive-pack
+func (b *Backend) gitEnv(r *http.Request, service string) []string {
+	// Identify the pusher: the authenticated user if auth is enabled
+	remoteUser := DefaultRemoteUser
+	if name, ok := users.FromContext(r.Context()); ok {
+		remoteUser = name
+	}
+
+	env := ServiceEnv(remoteUser, b.lghPath, gitProtocol(r))
+
+	// As git-http-backend does, record the pusher in the reflog
+	if service == serviceReceivePack {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
 		}
+		env = append(env,
+			"GIT_COMMITTER_NAME="+remoteUser,
+			"GIT_COMMITTER_EMAIL="+remoteUser+"@http."+host,
+		)
+	}
+
+	return env
+}
+
+// ServiceEnv returns the environment for running upload-pack or
+// receive-pack on behalf of remoteUser. Hooks load the LGH config from the
+// home directory, so the server environment is inherited.
+func ServiceEnv(remoteUser, lghPath, protocol string) []string {
+	env := append(os.Environ(),
+		"REMOTE_USER="+remoteUser,
+		"LGH_BIN="+lghPath,
+	)
+	if protocol = SanitizeProtocol(protocol); protocol != "" {
+		env = append(env, "GIT_PROTOCOL="+protocol)
 	}
+	return env
+}
+
+// SanitizeProtocol returns a client-supplied GIT_PROTOCOL value if it is
+// well formed, "" otherwise
+func SanitizeProtocol(proto string) string {
+	if !gitProtocolPattern.MatchString(proto) {
+		return ""
+	}
+	return proto
+}
+
+// gitProtocol returns the Git-Protocol request header if it is well formed
+func gitProtocol(r *http.Request) string {
+	return SanitizeProtocol(r.Header.Get("Git-Protocol"))
+}
+
+// isSmartService reports whether name is a smart HTTP service
+func isSmartService(name string) bool {
+	return name == serviceUploadPack || name == serviceReceivePack
+}
+
+// pktLine encodes s as a git pkt-line
+func pktLine(s string) string {
+	return fmt.Sprintf("%04x%s", len(s)+4, s)
+}
+
+// setNoCache marks a response as not cacheable
+func setNoCache(w http.ResponseWriter) {
+	w.Header().Set("Expires", "Fri, 01 Jan 1980 00:00:00 GMT")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Cache-Control", "no-cache, max-age=0, must-revalidate")
+}
+
+// flushWriter flushes the response after every write so packs stream to
+// the client instead of being buffered
+type flushWriter struct {
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if err == nil {
+		_ = f.rc.Flush()
+	}
+	return n, err
 }
 
 // parseRequest extracts the repository name and git path from the request
 func (b *Backend) parseRequest(r *http.Request) (string, string) {
 	path := r.URL.Path
 
-	// Match patterns like /repo.git/info/refs, /repo.git/git-upload-pack, etc.
-	re := regexp.MustCompile(`^/([^/]+\.git)(.*)$`)
-	matches := re.FindStringSubmatch(path)
-	if len(matches) != 3 {
+	// Match patterns like /repo.git/info/refs, /team/repo.git/git-upload-pack, etc.
+	name, gitPath, ok := registry.NameFromPath(path)
+	if !ok {
 		return "", ""
 	}
 
-	return matches[1], matches[2]
+	return name + ".git", gitPath
 }
 
 // isPushRequest checks if the request is a push operation
 func (b *Backend) isPushRequest(r *http.Request, gitPath string) bool {
+	return isPushRequest(r, gitPath)
+}
+
+// IsPushRequest reports whether r is a push operation.
+// The server uses it to enforce write permissions before the backend runs.
+func IsPushRequest(r *http.Request) bool {
+	return isPushRequest(r, r.URL.Path)
+}
+
+// isPushRequest checks if the request is a push operation
+func isPushRequest(r *http.Request, gitPath string) bool {
 	// Push requests are POST to git-receive-pack
 	if r.Method != http.MethodPost {
 		return false
@@ -0,0 +1,289 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package git
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when a ref, commit or path does not exist
+var ErrNotFound = errors.New("not found")
+
+// Commit is a commit as returned by the repository API
+type Commit struct {
+	SHA            string    `json:"sha"`
+	Parents        []string  `json:"parents"`
+	Author         string    `json:"author"`
+	AuthorEmail    string    `json:"author_email"`
+	AuthoredAt     time.Time `json:"authored_at"`
+	Committer      string    `json:"committer"`
+	CommitterEmail string    `json:"committer_email"`
+	CommittedAt    time.Time `json:"committed_at"`
+	Subject        string    `json:"subject"`
+}
+
+// commitFormat prints the Commit fields separated by \x1f, one record per \x1e
+const commitFormat = "%H%x1f%P%x1f%an%x1f%ae%x1f%aI%x1f%cn%x1f%ce%x1f%cI%x1f%s%x1e"
+
+// gitCommand runs git in repoPath with pathspecs taken literally, so that
+// user-supplied paths cannot use pathspec magic
+func gitCommand(repoPath string, args ...string) *exec.Cmd {
+	// nolint:gosec // G204: refs and paths are validated by the callers
+	cmd := exec.Command("git", append([]string{"-C", repoPath}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_LITERAL_PATHSPECS=1")
+	return cmd
+}
+
+// ValidRef reports whether ref can be passed to git as a revision
+func ValidRef(ref string) bool {
+	return ref != "" && !strings.HasPrefix(ref, "-") && !strings.ContainsAny(ref, " \t\n\x00")
+}
+
+// ResolveCommit returns the full hash of the commit ref points to
+func ResolveCommit(repoPath, ref string) (string, error) {
+	if !ValidRef(ref) {
+		return "", fmt.Errorf("invalid ref '%s'", ref)
+	}
+	output, err := gitCommand(repoPath, "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}").Output()
+	if err != nil {
+		return "", ErrNotFound
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ListCommits returns up to limit commits reachable from ref, newest first,
+// skipping the first skip. With a path, only commits touching it are listed.
+func ListCommits(repoPath, ref, path string, skip, limit int) ([]Commit, error) {
+	sha, err := ResolveCommit(repoPath, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"log", "--format=" + commitFormat, fmt.Sprintf("--skip=%d", skip), fmt.Sprintf("--max-count=%d", limit), sha}
+	if path != "" {
+		args = append(args, "--", path)
+	}
+	output, err := gitCommand(repoPath, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+	return parseCommits(string(output)), nil
+}
+
+// parseCommits parses git log output in commitFormat
+func parseCommits(output string) []Commit {
+	commits := []Commit{}
+	for _, record := range strings.Split(output, "\x1e") {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), "\x1f")
+		if len(fields) != 9 {
+			continue
+		}
+		c := Commit{
+			SHA:            fields[0],
+			Parents:        strings.Fields(fields[1]),
+			Author:         fields[2],
+			AuthorEmail:    fields[3],
+			Committer:      fields[5],
+			CommitterEmail: fields[6],
+			Subject:        fields[8],
+		}
+		c.AuthoredAt, _ = time.Parse(time.RFC3339, fields[4])
+		c.CommittedAt, _ = time.Parse(time.RFC3339, fields[7])
+		if c.Parents == nil {
+			c.Parents = []string{}
+		}
+		commits = append(commits, c)
+	}
+	return commits
+}
+
+// TreeEntry is a file, directory or submodule in a tree listing
+type TreeEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Type is "blob", "tree" or "commit" (submodule)
+	Type string `json:"type"`
+	Mode string `json:"mode"`
+	SHA  string `json:"sha"`
+	// Size is null for trees and submodules
+	Size       *int64  `json:"size"`
+	LastCommit *Commit `json:"last_commit"`
+}
+
+// LastCommits finds the last commit that changed each entry of the directory
+// dir in commit, in a single git log pass over at most maxCommits commits.
+// Entries last changed further back are missing from the result, which is
+// keyed by entry path.
+func LastCommits(ctx context.Context, repoPath, commit, dir string, entries []TreeEntry, maxCommits int) (map[string]Commit, error) {
+	result := make(map[string]Commit, len(entries))
+	wanted := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		wanted[e.Path] = true
+	}
+	if len(wanted) == 0 {
+		return result, nil
+	}
+
+	args := []string{"log", "-z", "--name-only", "--format=%x1d" + commitFormat, fmt.Sprintf("--max-count=%d", maxCommits), commit}
+	if dir != "" {
+		args = append(args, "--", dir)
+	}
+	cmd := gitCommand(repoPath, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+	// Stop git once every entry is resolved or the request is gone
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	// -z output: "\x1d<commit>\x1e" NUL "\n<file>" NUL "<file>" NUL ...
+	reader := bufio.NewReader(stdout)
+	var current *Commit
+	firstFile := false
+	for len(result) < len(wanted) && ctx.Err() == nil {
+		token, err := reader.ReadString(0)
+		if err != nil {
+			break
+		}
+		token = strings.TrimSuffix(token, "\x00")
+		if header := strings.TrimPrefix(token, "\n"); strings.HasPrefix(header, "\x1d") {
+			current = nil
+			if commits := parseCommits(strings.TrimPrefix(header, "\x1d")); len(commits) == 1 {
+				current = &commits[0]
+			}
+			firstFile = true
+			continue
+		}
+		if firstFile {
+			token = strings.TrimPrefix(token, "\n")
+			firstFile = false
+		}
+		if current == nil || token == "" {
+			continue
+		}
+
+		// Attribute the file to the entry of dir that contains it
+		rel := token
+		if dir != "" {
+			rel = strings.TrimPrefix(token, dir+"/")
+		}
+		entry, _, _ := strings.Cut(rel, "/")
+		if dir != "" {
+			entry = dir + "/" + entry
+		}
+		if _, done := result[entry]; wanted[entry] && !done {
+			result[entry] = *current
+		}
+	}
+	return result, ctx.Err()
+}
+
+// objectSpec names path in commit; the empty path is the root tree
+func objectSpec(commit, path string) string {
+	if path == "" {
+		return commit + "^{tree}"
+	}
+	return commit + ":" + path
+}
+
+// ObjectType returns the type of path in commit: "blob", "tree" or "commit"
+func ObjectType(repoPath, commit, path string) (string, error) {
+	output, err := gitCommand(repoPath, "cat-file", "-t", objectSpec(commit, path)).Output()
+	if err != nil {
+		return "", ErrNotFound
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ListTree returns the entries of the directory path in commit
+func ListTree(repoPath, commit, path string) ([]TreeEntry, error) {
+	output, err := gitCommand(repoPath, "ls-tree", "-l", "-z", objectSpec(commit, path)).Output()
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	entries := []TreeEntry{}
+	for _, line := range strings.Split(string(output), "\x00") {
+		// <mode> SP <type> SP <sha> SP+ <size> TAB <name>
+		meta, name, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 4 {
+			continue
+		}
+		entry := TreeEntry{Name: name, Path: name, Type: fields[1], Mode: fields[0], SHA: fields[2]}
+		if path != "" {
+			entry.Path = path + "/" + name
+		}
+		if size, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+			entry.Size = &size
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// BlobSize returns the size of the file path in commit
+func BlobSize(repoPath, commit, path string) (int64, error) {
+	output, err := gitCommand(repoPath, "cat-file", "-s", objectSpec(commit, path)).Output()
+	if err != nil {
+		return 0, ErrNotFound
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+}
+
+// blobReader streams a blob from git cat-file
+type blobReader struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+func (b *blobReader) Close() error {
+	_ = b.ReadCloser.Close()
+	return b.cmd.Wait()
+}
+
+// OpenBlob streams the content of the file path in commit
+func OpenBlob(repoPath, commit, path string) (io.ReadCloser, error) {
+	cmd := gitCommand(repoPath, "cat-file", "blob", objectSpec(commit, path))
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &blobReader{ReadCloser: stdout, cmd: cmd}, nil
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package git
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// newBrowseRepo creates a bare repository whose main branch has three
+// commits: README.md, then src/app.go, then a README.md change
+func newBrowseRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	barePath := filepath.Join(dir, "repo.git")
+	if err := InitBareRepo(barePath); err != nil {
+		t.Fatalf("InitBareRepo: %v", err)
+	}
+
+	work := filepath.Join(dir, "work")
+	run := func(args ...string) {
+		t.Helper()
+		args = append([]string{"-C", work, "-c", "user.name=t", "-c", "user.email=t@t"}, args...)
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(work, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if out, err := exec.Command("git", "init", "-q", work).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %s", out)
+	}
+	write("README.md", "hello\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "Add README")
+	write("src/app.go", "package main\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "Add app")
+	run("tag", "-a", "v1.0", "-m", "Release 1.0")
+	write("README.md", "hello world\n")
+	run("commit", "-q", "-am", "Update README")
+	run("push", "-q", barePath, "HEAD:refs/heads/main", "v1.0")
+	return barePath
+}
+
+func TestListCommits(t *testing.T) {
+	barePath := newBrowseRepo(t)
+
+	commits, err := ListCommits(barePath, "main", "", 0, 10)
+	if err != nil {
+		t.Fatalf("ListCommits: %v", err)
+	}
+	if len(commits) != 3 || commits[0].Subject != "Update README" || commits[2].Subject != "Add README" {
+		t.Fatalf("ListCommits(main) = %+v", commits)
+	}
+	if len(commits[0].Parents) != 1 || commits[0].Parents[0] != commits[1].SHA || len(commits[2].Parents) != 0 {
+		t.Errorf("unexpected parents: %+v", commits)
+	}
+	if commits[0].Author != "t" || commits[0].AuthoredAt.IsZero() {
+		t.Errorf("unexpected author: %+v", commits[0])
+	}
+
+	// Pagination and path filter
+	if page, _ := ListCommits(barePath, "main", "", 1, 1); len(page) != 1 || page[0].SHA != commits[1].SHA {
+		t.Errorf("ListCommits(skip 1, limit 1) = %+v", page)
+	}
+	if touched, _ := ListCommits(barePath, "main", "README.md", 0, 10); len(touched) != 2 {
+		t.Errorf("ListCommits(README.md) = %d commits, want 2", len(touched))
+	}
+
+	// Annotated tags resolve to the tagged commit
+	if sha, err := ResolveCommit(barePath, "v1.0"); err != nil || sha != commits[1].SHA {
+		t.Errorf("ResolveCommit(v1.0) = %s, %v", sha, err)
+	}
+	if tags, err := GetTags(barePath); err != nil || len(tags) != 1 || tags[0].Name != "v1.0" ||
+		tags[0].SHA != commits[1].SHA || tags[0].TagSHA == "" {
+		t.Errorf("GetTags = %+v, %v", tags, err)
+	}
+	if last, err := GetLastCommit(barePath, "main"); err != nil || last.SHA != commits[0].SHA {
+		t.Errorf("GetLastCommit(main) = %+v, %v", last, err)
+	}
+	if _, err := ListCommits(barePath, "missing", "", 0, 10); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ListCommits(missing) error = %v, want ErrNotFound", err)
+	}
+	if _, err := ListCommits(barePath, "--all", "", 0, 10); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("ListCommits(--all) error = %v, want invalid ref", err)
+	}
+}
+
+func TestListTreeAndOpenBlob(t *testing.T) {
+	barePath := newBrowseRepo(t)
+	sha, err := ResolveCommit(barePath, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ListTree(barePath, sha, "")
+	if err != nil {
+		t.Fatalf("ListTree: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name != "README.md" || entries[1].Type != "tree" || entries[1].Size != nil {
+		t.Fatalf("ListTree(root) = %+v", entries)
+	}
+	if entries[0].Mode != "100644" || entries[0].Size == nil || *entries[0].Size != 12 {
+		t.Errorf("README.md entry = %+v", entries[0])
+	}
+
+	entries, err = ListTree(barePath, sha, "src")
+	if err != nil || len(entries) != 1 || entries[0].Path != "src/app.go" {
+		t.Fatalf("ListTree(src) = %+v, %v", entries, err)
+	}
+
+	if typ, _ := ObjectType(barePath, sha, "src/app.go"); typ != "blob" {
+		t.Errorf("ObjectType(src/app.go) = %q", typ)
+	}
+	if _, err := ObjectType(barePath, sha, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ObjectType(missing) error = %v", err)
+	}
+
+	blob, err := OpenBlob(barePath, sha, "README.md")
+	if err != nil {
+		t.Fatalf("OpenBlob: %v", err)
+	}
+	content, _ := io.ReadAll(blob)
+	if err := blob.Close(); err != nil || string(content) != "hello world\n" {
+		t.Errorf("OpenBlob(README.md) = %q, %v", content, err)
+	}
+}
+
+func TestLastCommits(t *testing.T) {
+	barePath := newBrowseRepo(t)
+	sha, err := ResolveCommit(barePath, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _ := ListTree(barePath, sha, "")
+	last, err := LastCommits(context.Background(), barePath, sha, "", entries, 100)
+	if err != nil {
+		t.Fatalf("LastCommits: %v", err)
+	}
+	if last["README.md"].Subject != "Update README" || last["src"].Subject != "Add app" {
+		t.Errorf("LastCommits(root) = %+v", last)
+	}
+
+	entries, _ = ListTree(barePath, sha, "src")
+	if last, err = LastCommits(context.Background(), barePath, sha, "src", entries, 100); err != nil || last["src/app.go"].Subject != "Add app" {
+		t.Errorf("LastCommits(src) = %+v, %v", last, err)
+	}
+
+	// Entries changed before the searched depth are left out
+	entries, _ = ListTree(barePath, sha, "")
+	if last, err = LastCommits(context.Background(), barePath, sha, "", entries, 1); err != nil || len(last) != 1 {
+		t.Errorf("LastCommits(depth 1) = %+v, %v", last, err)
+	}
+}
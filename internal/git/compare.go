@@ -0,0 +1,191 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package git
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FileDiff is a file changed between two commits
+type FileDiff struct {
+	Path string `json:"path"`
+	// OldPath is set for renames and copies
+	OldPath string `json:"old_path,omitempty"`
+	// Status is added, modified, deleted, renamed, copied or type_changed
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Binary    bool   `json:"binary"`
+}
+
+// diffStatus maps git's --name-status letters to FileDiff statuses
+var diffStatus = map[byte]string{
+	'A': "added",
+	'M': "modified",
+	'D': "deleted",
+	'R': "renamed",
+	'C': "copied",
+	'T': "type_changed",
+}
+
+// MergeBase returns the best common ancestor of two commits
+func MergeBase(repoPath, a, b string) (string, error) {
+	output, err := gitCommand(repoPath, "merge-base", a, b).Output()
+	if err != nil {
+		return "", ErrNotFound
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CountCommits returns the number of commits reachable from to but not from from
+func CountCommits(repoPath, from, to string) (int, error) {
+	output, err := gitCommand(repoPath, "rev-list", "--count", from+".."+to).Output()
+	if err != nil {
+		return 0, fmt.Errorf("git rev-list failed: %w", err)
+	}
+	return strconv.Atoi(strings.TrimSpace(string(output)))
+}
+
+// ListCommitRange returns up to limit commits reachable from head but not
+// from base, newest first. base and head must be resolved hashes.
+func ListCommitRange(repoPath, base, head string, limit int) ([]Commit, error) {
+	output, err := gitCommand(repoPath, "log", "--format="+commitFormat,
+		fmt.Sprintf("--max-count=%d", limit), base+".."+head).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+	return parseCommits(string(output)), nil
+}
+
+// diffArgs builds the arguments of a diff between two resolved commits,
+// limited to paths if any
+func diffArgs(base, head string, paths []string, options ...string) []string {
+	args := append([]string{"diff", "--no-color", "--no-ext-diff", "-M"}, options...)
+	args = append(args, base, head)
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	return args
+}
+
+// changedFiles runs the git diff command args with --name-status and
+// returns the changed files, without line counts
+func changedFiles(repoPath string, args []string) ([]FileDiff, error) {
+	args = append([]string{args[0], "--name-status", "-z"}, args[1:]...)
+	output, err := gitCommand(repoPath, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+
+	// <status>\0<path>\0, or <status>\0<old>\0<new>\0 for renames and copies
+	files := []FileDiff{}
+	fields := strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i] == "" {
+			break
+		}
+		file := FileDiff{Path: fields[i+1], Status: diffStatus[fields[i][0]]}
+		if file.Status == "renamed" || file.Status == "copied" {
+			if i+2 >= len(fields) {
+				break
+			}
+			file.OldPath, file.Path = fields[i+1], fields[i+2]
+			i++
+		}
+		if file.Status == "" {
+			file.Status = "modified"
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// DiffFiles returns the files changed between two resolved commits with
+// their line counts: GetChangedFiles with statuses, renames and stats.
+func DiffFiles(repoPath, base, head string, paths []string) ([]FileDiff, error) {
+	files, err := changedFiles(repoPath, diffArgs(base, head, paths))
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(files))
+	for i, f := range files {
+		index[f.Path] = i
+	}
+
+	output, err := gitCommand(repoPath, diffArgs(base, head, paths, "--numstat", "-z")...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %w", err)
+	}
+
+	// <add>\t<del>\t<path>\0, or <add>\t<del>\t\0<old>\0<new>\0 for renames
+	records := strings.Split(string(output), "\x00")
+	for i := 0; i < len(records); i++ {
+		parts := strings.SplitN(records[i], "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		path := parts[2]
+		if path == "" && i+2 < len(records) {
+			path = records[i+2]
+			i += 2
+		}
+		n, ok := index[path]
+		if !ok {
+			continue
+		}
+		if parts[0] == "-" {
+			files[n].Binary = true
+			continue
+		}
+		files[n].Additions, _ = strconv.Atoi(parts[0])
+		files[n].Deletions, _ = strconv.Atoi(parts[1])
+	}
+	return files, nil
+}
+
+// DiffPatch returns the unified diff between two resolved commits. It reads
+// at most maxBytes and reports whether the diff was longer.
+func DiffPatch(repoPath, base, head string, paths []string, maxBytes int64) ([]byte, bool, error) {
+	cmd := gitCommand(repoPath, diffArgs(base, head, paths)...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, false, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, false, err
+	}
+
+	patch, err := io.ReadAll(io.LimitReader(stdout, maxBytes+1))
+	truncated := int64(len(patch)) > maxBytes
+	if truncated {
+		// Stop git instead of draining a huge diff
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return patch[:maxBytes], true, nil
+	}
+	if waitErr := cmd.Wait(); err == nil && waitErr != nil {
+		err = fmt.Errorf("git diff failed: %w", waitErr)
+	}
+	return patch, false, err
+}
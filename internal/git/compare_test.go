@@ -0,0 +1,94 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	barePath := newBrowseRepo(t)
+	tag, _ := ResolveCommit(barePath, "v1.0")
+	main, _ := ResolveCommit(barePath, "main")
+
+	if base, err := MergeBase(barePath, tag, main); err != nil || base != tag {
+		t.Fatalf("MergeBase(v1.0, main) = %s, %v", base, err)
+	}
+	if ahead, _ := CountCommits(barePath, tag, main); ahead != 1 {
+		t.Errorf("ahead = %d, want 1", ahead)
+	}
+	if behind, _ := CountCommits(barePath, main, tag); behind != 0 {
+		t.Errorf("behind = %d, want 0", behind)
+	}
+	if commits, _ := ListCommitRange(barePath, tag, main, 10); len(commits) != 1 || commits[0].Subject != "Update README" {
+		t.Errorf("ListCommitRange = %+v", commits)
+	}
+
+	files, err := DiffFiles(barePath, tag, main, nil)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("DiffFiles = %+v, %v", files, err)
+	}
+	if f := files[0]; f.Path != "README.md" || f.Status != "modified" || f.Additions != 1 || f.Deletions != 1 {
+		t.Errorf("DiffFiles = %+v", f)
+	}
+	if files, _ := DiffFiles(barePath, tag, main, []string{"src"}); len(files) != 0 {
+		t.Errorf("DiffFiles(src) = %+v, want none", files)
+	}
+
+	patch, truncated, err := DiffPatch(barePath, tag, main, nil, 1<<20)
+	if err != nil || truncated || !strings.Contains(string(patch), "+hello world") {
+		t.Errorf("DiffPatch = %q, %v, %v", patch, truncated, err)
+	}
+	if patch, truncated, _ := DiffPatch(barePath, tag, main, nil, 10); !truncated || len(patch) != 10 {
+		t.Errorf("DiffPatch(10 bytes) = %d bytes, truncated %v", len(patch), truncated)
+	}
+}
+
+func TestDiffFilesRename(t *testing.T) {
+	barePath := newBrowseRepo(t)
+	work := filepath.Join(t.TempDir(), "work")
+	for _, args := range [][]string{
+		{"clone", "-q", "-b", "main", barePath, work},
+		{"-C", work, "mv", "src/app.go", "src/main.go"},
+		{"-C", work, "-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m", "Rename app"},
+		{"-C", work, "push", "-q", "origin", "HEAD:refs/heads/moved"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+
+	main, _ := ResolveCommit(barePath, "main")
+	moved, _ := ResolveCommit(barePath, "moved")
+	files, err := DiffFiles(barePath, main, moved, nil)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("DiffFiles = %+v, %v", files, err)
+	}
+	if f := files[0]; f.Status != "renamed" || f.OldPath != "src/app.go" || f.Path != "src/main.go" {
+		t.Errorf("DiffFiles = %+v", f)
+	}
+	if names, err := GetChangedFiles(barePath, main, moved); err != nil || len(names) != 1 || names[0] != "src/main.go" {
+		t.Errorf("GetChangedFiles = %v, %v", names, err)
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package git
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+// DaemonExportFile is the marker that opts a repository into git:// access,
+// as with git-daemon
+const DaemonExportFile = "git-daemon-export-ok"
+
+// daemonRequestTimeout bounds how long a client may take to send its request
+const daemonRequestTimeout = 10 * time.Second
+
+// daemonIdleTimeout is passed to upload-pack as --timeout (seconds)
+const daemonIdleTimeout = 600
+
+// Daemon serves read-only git:// (upload-pack and upload-archive) for
+// exported repositories.
+// Like git-daemon it has no authentication.
+type Daemon struct {
+	reposDir string
+	gitPath  string
+	limiter  *Limiter
+
+	// Hidden, if set, reports repositories that are not served even if
+	// exported, such as those restricted by an access list
+	Hidden func(name string) bool
+
+	// OnRequest, if set, is called after each request with the service,
+	// repository path, client address and error (nil on success)
+	OnRequest func(service, path, remote string, err error, d time.Duration)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	ln     net.Listener
+}
+
+// NewDaemon creates a git:// daemon for repositories in reposDir
+func NewDaemon(reposDir string) (*Daemon, error) {
+	gitPath, err := CheckGitInstalled()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Daemon{reposDir: reposDir, gitPath: gitPath, ctx: ctx, cancel: cancel}, nil
+}
+
+// SetLimiter shares the git process limit with the HTTP backend
+func (d *Daemon) SetLimiter(l *Limiter) {
+	d.limiter = l
+}
+
+// IsDaemonExported reports whether a bare repository is exported over git://
+func IsDaemonExported(barePath string) bool {
+	_, err := os.Stat(filepath.Join(barePath, DaemonExportFile))
+	return err == nil
+}
+
+// SetDaemonExport creates or removes the git-daemon-export-ok marker
+func SetDaemonExport(barePath string, export bool) error {
+	marker := filepath.Join(barePath, DaemonExportFile)
+	if !export {
+		if err := os.Remove(marker); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", DaemonExportFile, err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(marker, nil, 0600); err != nil {
+		return fmt.Errorf("failed to create %s: %w", DaemonExportFile, err)
+	}
+	return nil
+}
+
+// Serve accepts git:// connections on ln until Close is called
+func (d *Daemon) Serve(ln net.Listener) error {
+	d.mu.Lock()
+	d.ln = ln
+	d.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if d.ctx.Err() != nil {
+				return nil
+			}
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				continue
+			}
+			return err
+		}
+		go d.handle(conn)
+	}
+}
+
+// Close stops accepting connections and kills running upload-packs
+func (d *Daemon) Close() error {
+	d.cancel()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.ln != nil {
+		return d.ln.Close()
+	}
+	return nil
+}
+
+// handle serves one connection
+func (d *Daemon) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	start := time.Now()
+
+	_ = conn.SetReadDeadline(time.Now().Add(daemonRequestTimeout))
+	reader := bufio.NewReader(conn)
+	service, reqPath, extra, err := readDaemonRequest(reader)
+	if err != nil {
+		d.report("", "", conn, err, start)
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	barePath, release, err := d.prepare(service, reqPath)
+	if err != nil {
+		// Clients print ERR packets as "fatal: remote error: ..."
+		_, _ = io.WriteString(conn, pktLine("ERR "+err.Error()))
+		d.report(service, reqPath, conn, err, start)
+		return
+	}
+	defer release()
+
+	if service == serviceUploadArchive {
+		err = d.uploadArchive(conn, reader, barePath)
+	} else {
+		err = d.uploadPack(conn, reader, barePath, extra)
+	}
+	d.report(service, reqPath, conn, err, start)
+}
+
+// prepare checks the request and takes a process slot
+func (d *Daemon) prepare(service, reqPath string) (string, func(), error) {
+	if service != serviceUploadPack && service != serviceUploadArchive {
+		return "", nil, fmt.Errorf("service not enabled: '%s'", strings.TrimPrefix(service, "git-"))
+	}
+
+	barePath, ok := d.resolve(reqPath)
+	if !ok {
+		return "", nil, fmt.Errorf("access denied or repository not exported: %s", reqPath)
+	}
+
+	release := func() {}
+	if d.limiter != nil {
+		var err error
+		if release, err = d.limiter.Acquire(d.ctx); err != nil {
+			return "", nil, fmt.Errorf("server busy, try again later")
+		}
+	}
+	return barePath, release, nil
+}
+
+// uploadPack runs upload-pack with the connection as stdin and stdout
+func (d *Daemon) uploadPack(conn net.Conn, stdin io.Reader, barePath string, extra []string) error {
+	// nolint:gosec // G204: barePath is an exported repository inside reposDir
+	cmd := exec.CommandContext(d.ctx, d.gitPath, "upload-pack", "--strict",
+		"--timeout="+strconv.Itoa(daemonIdleTimeout), barePath)
+	cmd.Env = os.Environ()
+	if proto := daemonProtocol(extra); proto != "" {
+		cmd.Env = append(cmd.Env, "GIT_PROTOCOL="+proto)
+	}
+	cmd.Stdin = stdin
+	cmd.Stdout = conn
+	cmd.WaitDelay = processKillDelay
+	return cmd.Run()
+}
+
+// uploadArchive runs upload-archive for 'git archive --remote'. It only
+// archives what the refs of the repository reach.
+func (d *Daemon) uploadArchive(conn net.Conn, stdin io.Reader, barePath string) error {
+	// nolint:gosec // G204: barePath is an exported repository inside reposDir
+	cmd := exec.CommandContext(d.ctx, d.gitPath, "upload-archive", barePath)
+	cmd.Env = os.Environ()
+	cmd.Stdin = stdin
+	cmd.Stdout = conn
+	cmd.WaitDelay = processKillDelay
+	return cmd.Run()
+}
+
+// resolve maps a request path like "/app.git", "/lgh/app" or "/team/app.git"
+// to an exported bare repository inside reposDir
+func (d *Daemon) resolve(reqPath string) (string, bool) {
+	name := strings.TrimSuffix(strings.TrimPrefix(reqPath, "/"), ".git")
+	name = strings.TrimPrefix(name, registry.LegacyNamespace+"/")
+	if registry.ValidateName(name) != nil || (d.Hidden != nil && d.Hidden(name)) {
+		return "", false
+	}
+
+	barePath := registry.BarePathFor(d.reposDir, name)
+	if !IsBareRepo(barePath) || !IsDaemonExported(barePath) {
+		return "", false
+	}
+	return barePath, true
+}
+
+// report calls OnRequest if set
+func (d *Daemon) report(service, reqPath string, conn net.Conn, err error, start time.Time) {
+	if d.OnRequest != nil {
+		d.OnRequest(service, reqPath, conn.RemoteAddr().String(), err, time.Since(start))
+	}
+}
+
+// readDaemonRequest parses the initial git:// request:
+// pkt-line("git-upload-pack /path\0host=example.com\0\0version=2\0")
+func readDaemonRequest(r *bufio.Reader) (service, path string, extra []string, err error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return "", "", nil, fmt.Errorf("failed to read request: %w", err)
+	}
+	size, err := strconv.ParseUint(string(header[:]), 16, 16)
+	if err != nil || size <= 4 {
+		return "", "", nil, fmt.Errorf("invalid request")
+	}
+	payload := make([]byte, size-4)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return "", "", nil, fmt.Errorf("failed to read request: %w", err)
+	}
+
+	fields := strings.Split(strings.TrimSuffix(string(payload), "\n"), "\x00")
+	service, path, ok := strings.Cut(fields[0], " ")
+	if !ok {
+		return "", "", nil, fmt.Errorf("invalid request")
+	}
+	// fields[1] is host=...; extra parameters follow an empty field
+	for i := 2; i < len(fields); i++ {
+		if fields[i] != "" {
+			extra = append(extra, fields[i])
+		}
+	}
+	return service, path, extra, nil
+}
+
+// daemonProtocol builds GIT_PROTOCOL from the extra request parameters
+func daemonProtocol(extra []string) string {
+	var params []string
+	for _, p := range extra {
+		if gitProtocolPattern.MatchString(p) {
+			params = append(params, p)
+		}
+	}
+	return strings.Join(params, ":")
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package git
+
+import (
+	"net"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDaemonServesExportedRepos(t *testing.T) {
+	dir := t.TempDir()
+	reposDir := filepath.Join(dir, "repos")
+	barePath := filepath.Join(reposDir, "app.git")
+	if err := InitBareRepo(barePath); err != nil {
+		t.Fatalf("InitBareRepo: %v", err)
+	}
+
+	work := filepath.Join(dir, "work")
+	for _, args := range [][]string{
+		{"init", "-q", work},
+		{"-C", work, "-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"-C", work, "push", "-q", barePath, "HEAD:refs/heads/main"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+
+	daemon, err := NewDaemon(reposDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hideApp atomic.Bool
+	daemon.Hidden = func(name string) bool { return name == "app" && hideApp.Load() }
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = daemon.Serve(ln) }()
+	defer daemon.Close()
+
+	url := "git://" + ln.Addr().String() + "/app.git"
+
+	out, err := exec.Command("git", "ls-remote", url).CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "not exported") {
+		t.Fatalf("expected unexported repo to be refused, got %v: %s", err, out)
+	}
+
+	if err := SetDaemonExport(barePath, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range []string{"0", "2"} {
+		out, err = exec.Command("git", "-c", "protocol.version="+version, "ls-remote", url).CombinedOutput()
+		if err != nil || !strings.Contains(string(out), "refs/heads/main") {
+			t.Fatalf("protocol v%s ls-remote failed: %v: %s", version, err, out)
+		}
+	}
+
+	// Repositories with an access list stay hidden even when exported
+	hideApp.Store(true)
+	out, err = exec.Command("git", "ls-remote", url).CombinedOutput()
+	if err == nil {
+		t.Errorf("expected hidden repo to be refused: %s", out)
+	}
+	hideApp.Store(false)
+
+	out, err = exec.Command("git", "archive", "--remote="+url, "--format=tar", "main").CombinedOutput()
+	if err != nil {
+		t.Errorf("git archive --remote failed: %v: %s", err, out)
+	}
+
+	out, err = exec.Command("git", "-C", work, "push", url, "HEAD:refs/heads/other").CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "service not enabled") {
+		t.Errorf("expected push to be refused, got %v: %s", err, out)
+	}
+
+	out, err = exec.Command("git", "ls-remote", "git://"+ln.Addr().String()+"/../repos/app.git").CombinedOutput()
+	if err == nil {
+		t.Errorf("expected path traversal to be refused: %s", out)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ZeroHash is the object name git uses for a ref that does not exist
+const ZeroHash = "0000000000000000000000000000000000000000"
+
+// managedHookMarker identifies hooks written by LGH
+const managedHookMarker = "# Managed by LGH"
+
+// ManagedHooks lists the hooks LGH installs in bare repositories
+var ManagedHooks = []string{"pre-receive", "post-receive"}
+
+// RefUpdate is one "old new ref" line passed to receive hooks
+type RefUpdate struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+	Ref string `json:"ref"`
+}
+
+// Action returns "created", "updated" or "deleted"
+func (u RefUpdate) Action() string {
+	switch {
+	case u.Old == ZeroHash:
+		return "created"
+	case u.New == ZeroHash:
+		return "deleted"
+	default:
+		return "updated"
+	}
+}
+
+// ParseRefUpdates reads "old new ref" lines as given to pre- and post-receive hooks
+func ParseRefUpdates(r io.Reader) ([]RefUpdate, error) {
+	var updates []RefUpdate
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("malformed ref update line: %q", scanner.Text())
+		}
+		updates = append(updates, RefUpdate{Old: fields[0], New: fields[1], Ref: fields[2]})
+	}
+	return updates, scanner.Err()
+}
+
+// hookScript returns the script for a managed hook. It only does work for
+// pushes through the LGH server, which sets LGH_BIN to its own executable.
+func hookScript(hook string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s. Changes are overwritten; delete this line to take over the hook.
+[ -n "$LGH_BIN" ] || exit 0
+exec "$LGH_BIN" hook %s
+`, managedHookMarker, hook)
+}
+
+// InstallHooks writes the managed hooks into a bare repository.
+// Hooks that were not written by LGH are left alone and reported as an error
+// after the remaining hooks have been installed.
+func InstallHooks(barePath string) error {
+	hooksDir := filepath.Join(barePath, "hooks")
+	if err := os.MkdirAll(hooksDir, 0700); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+
+	var firstErr error
+	for _, hook := range ManagedHooks {
+		hookPath := filepath.Join(hooksDir, hook)
+
+		// nolint:gosec // G304: path is internally constructed and trusted
+		if data, err := os.ReadFile(hookPath); err == nil && !strings.Contains(string(data), managedHookMarker) {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s hook in %s is not managed by LGH; remove it to enable LGH hooks", hook, barePath)
+			}
+			continue
+		}
+
+		// nolint:gosec // G306: hooks must be executable
+		if err := os.WriteFile(hookPath, []byte(hookScript(hook)), 0700); err != nil {
+			return fmt.Errorf("failed to write %s hook: %w", hook, err)
+		}
+	}
+
+	return firstErr
+}
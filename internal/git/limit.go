@@ -0,0 +1,110 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package git
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrQueueFull is returned by Limiter.Acquire when all slots are busy and
+// the wait queue is full
+var ErrQueueFull = errors.New("too many concurrent git operations")
+
+// Limiter caps the number of concurrent upload-pack/receive-pack processes.
+// Requests beyond the cap wait in a bounded queue.
+type Limiter struct {
+	slots    chan struct{}
+	maxQueue int
+
+	mu     sync.Mutex
+	queued int
+}
+
+// LimiterStats is a snapshot of the limiter state
+type LimiterStats struct {
+	Running      int `json:"running"`
+	Queued       int `json:"queued"`
+	MaxProcesses int `json:"max_processes"`
+	MaxQueue     int `json:"max_queue"`
+}
+
+// NewLimiter creates a Limiter allowing maxProcs concurrent processes and
+// up to maxQueue waiting requests. maxProcs must be positive.
+func NewLimiter(maxProcs, maxQueue int) *Limiter {
+	if maxProcs < 1 {
+		maxProcs = 1
+	}
+	if maxQueue < 0 {
+		maxQueue = 0
+	}
+	return &Limiter{
+		slots:    make(chan struct{}, maxProcs),
+		maxQueue: maxQueue,
+	}
+}
+
+// Acquire takes a slot, waiting in the queue if necessary. The returned
+// function releases the slot. It fails with ErrQueueFull when the queue is
+// full, or with the context error if ctx ends while waiting.
+func (l *Limiter) Acquire(ctx context.Context) (func(), error) {
+	release := func() { <-l.slots }
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	l.mu.Lock()
+	if l.queued >= l.maxQueue {
+		l.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+	l.queued++
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		l.queued--
+		l.mu.Unlock()
+	}()
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Stats returns the current number of running and queued operations
+func (l *Limiter) Stats() LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return LimiterStats{
+		Running:      len(l.slots),
+		Queued:       l.queued,
+		MaxProcesses: cap(l.slots),
+		MaxQueue:     l.maxQueue,
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package git
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiterQueue(t *testing.T) {
+	l := NewLimiter(1, 1)
+
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first acquire: %v", err)
+	}
+
+	acquired := make(chan func())
+	go func() {
+		r, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Errorf("queued acquire: %v", err)
+			close(acquired)
+			return
+		}
+		acquired <- r
+	}()
+
+	// Wait for the second request to enter the queue
+	deadline := time.Now().Add(2 * time.Second)
+	for l.Stats().Queued != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("request was not queued")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if _, err := l.Acquire(context.Background()); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	release()
+	second := <-acquired
+	if second == nil {
+		t.Fatal("queued request did not acquire a slot")
+	}
+
+	stats := l.Stats()
+	if stats.Running != 1 || stats.Queued != 0 {
+		t.Errorf("unexpected stats after handoff: %+v", stats)
+	}
+	second()
+}
+
+func TestLimiterCancel(t *testing.T) {
+	l := NewLimiter(1, 4)
+	release, _ := l.Acquire(context.Background())
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if q := l.Stats().Queued; q != 0 {
+		t.Errorf("cancelled request still queued: %d", q)
+	}
+}
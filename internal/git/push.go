@@ -0,0 +1,79 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package git
+
+import (
+	"strings"
+
+	"github.com/JoeGlenn1213/lgh/internal/event"
+)
+
+// PublishRefUpdates emits git.push and git.tag events for the ref updates
+// of one push, as reported by the managed post-receive hook.
+// repoName is the repository path relative to the repos directory.
+func PublishRefUpdates(barePath, repoName, pusher, pushID string, updates []RefUpdate) {
+	// Separate tag changes from branch changes
+	branchChanges := make(map[string]map[string]string)
+	tagChanges := make(map[string]map[string]string)
+
+	for _, u := range updates {
+		change := map[string]string{"old": u.Old, "new": u.New, "action": u.Action()}
+		if strings.HasPrefix(u.Ref, "refs/tags/") {
+			tagChanges[u.Ref] = change
+		} else {
+			branchChanges[u.Ref] = change
+		}
+	}
+
+	// Emit branch push event
+	if len(branchChanges) > 0 {
+		// Calculate changed files for each updated ref
+		changedFiles := make(map[string][]string)
+		for ref, change := range branchChanges {
+			if change["action"] == "updated" || change["action"] == "created" {
+				files, err := GetChangedFiles(barePath, change["old"], change["new"])
+				if err == nil && len(files) > 0 {
+					changedFiles[ref] = files
+				}
+			}
+		}
+
+		payload := map[string]interface{}{
+			"changes": branchChanges,
+			"pusher":  pusher,
+			"push_id": pushID,
+		}
+		if len(changedFiles) > 0 {
+			payload["changed_files"] = changedFiles
+		}
+
+		event.Publish(event.GitPush, repoName, payload)
+	}
+
+	// Emit tag event
+	if len(tagChanges) > 0 {
+		event.Publish(event.GitTag, repoName, map[string]interface{}{
+			"changes": tagChanges,
+			"pusher":  pusher,
+			"push_id": pushID,
+		})
+	}
+}
@@ -21,7 +21,10 @@
 package git
 
 import (
+	"bytes"
+	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 )
 
@@ -50,6 +53,38 @@ func GetRefs(repoPath string) (map[string]string, error) {
 	return refs, nil
 }
 
+// Tag is a tag of a repository
+type Tag struct {
+	Name string
+	// SHA is the tagged object; TagSHA the tag object of annotated tags
+	SHA    string
+	TagSHA string
+}
+
+// GetTags returns the tags of a repository, sorted by name
+func GetTags(repoPath string) ([]Tag, error) {
+	output, err := gitCommand(repoPath, "for-each-ref", "--sort=refname",
+		"--format=%(refname:lstrip=2)%1f%(objectname)%1f%(*objectname)", "refs/tags").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	tags := []Tag{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 3 {
+			continue
+		}
+		tag := Tag{Name: fields[0], SHA: fields[1]}
+		// Annotated tags point to a tag object: report the object it tags
+		if fields[2] != "" {
+			tag.SHA, tag.TagSHA = fields[2], fields[1]
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
 // GetChangedFiles returns the list of files changed between two commits.
 // For created refs, oldHash should be the empty tree hash or parent of first commit.
 // For deleted refs, returns empty slice.
@@ -60,26 +95,101 @@ func GetChangedFiles(repoPath, oldHash, newHash string) ([]string, error) {
 	}
 
 	// Handle creation - diff against empty tree or use --root
-	var cmd *exec.Cmd
+	var args []string
 	if oldHash == "" || oldHash == "0000000000000000000000000000000000000000" {
 		// New branch/tag - diff against empty tree or show all files in first commit
-		cmd = exec.Command("git", "-C", repoPath, "diff-tree", "--no-commit-id", "--name-only", "-r", newHash)
+		args = []string{"diff-tree", "--no-commit-id", "-r", newHash}
 	} else {
 		// Updated branch - diff between old and new
-		cmd = exec.Command("git", "-C", repoPath, "diff", "--name-only", oldHash, newHash)
+		args = []string{"diff", oldHash, newHash}
 	}
 
-	output, err := cmd.Output()
+	files, err := changedFiles(repoPath, args)
 	if err != nil {
 		return nil, err
 	}
-
-	files := strings.Split(strings.TrimSpace(string(output)), "\n")
 	result := make([]string, 0, len(files))
 	for _, f := range files {
-		if f != "" {
-			result = append(result, f)
-		}
+		result = append(result, f.Path)
 	}
 	return result, nil
 }
+
+// IsAncestor reports whether ancestor is reachable from commit,
+// i.e. whether moving a ref from ancestor to commit is a fast-forward.
+func IsAncestor(repoPath, ancestor, commit string) (bool, error) {
+	cmd := exec.Command("git", "-C", repoPath, "merge-base", "--is-ancestor", ancestor, commit)
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, err
+}
+
+// MergeCommits returns the merge commits a ref update introduces.
+// For a new ref, commits already reachable from existing refs are skipped.
+func MergeCommits(repoPath, oldHash, newHash string) ([]string, error) {
+	args := []string{"-C", repoPath, "rev-list", "--min-parents=2"}
+	if oldHash == "" || oldHash == "0000000000000000000000000000000000000000" {
+		args = append(args, newHash, "--not", "--all")
+	} else {
+		args = append(args, oldHash+".."+newHash)
+	}
+
+	// nolint:gosec // G204: hashes come from git itself
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// Blob is a file object introduced by a push
+type Blob struct {
+	Hash string
+	Path string
+	Size int64
+}
+
+// NewBlobs returns the blobs reachable from the given commits that no
+// existing ref already references. In a pre-receive hook these are the
+// files the push is about to add.
+func NewBlobs(repoPath string, commits []string) ([]Blob, error) {
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	args := append([]string{"-C", repoPath, "rev-list", "--objects"}, commits...)
+	args = append(args, "--not", "--all")
+	// nolint:gosec // G204: hashes come from git itself
+	objects, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-list failed: %w", err)
+	}
+
+	// cat-file echoes the rest of each input line (the path) after the size
+	cmd := exec.Command("git", "-C", repoPath, "cat-file",
+		"--batch-check=%(objecttype) %(objectname) %(objectsize) %(rest)")
+	cmd.Stdin = bytes.NewReader(objects)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file failed: %w", err)
+	}
+
+	var blobs []Blob
+	for _, line := range strings.Split(string(output), "\n") {
+		parts := strings.SplitN(line, " ", 4)
+		if len(parts) < 4 || parts[0] != "blob" {
+			continue
+		}
+		size, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		blobs = append(blobs, Blob{Hash: parts[1], Path: parts[3], Size: size})
+	}
+	return blobs, nil
+}
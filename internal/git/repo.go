@@ -34,14 +34,6 @@ type Remote struct {
 	URL  string
 }
 
-// CommitInfo represents basic commit information
-type CommitInfo struct {
-	Hash   string
-	Author string
-	Date   string
-	Msg    string
-}
-
 // CheckGitInstalled checks if git is installed and returns its path
 func CheckGitInstalled() (string, error) {
 	path, err := exec.LookPath("git")
@@ -80,7 +72,11 @@ func InitBareRepo(barePath string) error {
 		return fmt.Errorf("failed to init bare repo: %s, %w", string(output), err)
 	}
 
-	return nil
+	if err := ConfigureServing(barePath); err != nil {
+		return err
+	}
+
+	return InstallHooks(barePath)
 }
 
 // InitRepo initializes a git repository at the specified path
@@ -293,28 +289,18 @@ func GetBranches(repoPath string) ([]string, error) {
 	return branches, nil
 }
 
-// GetLastCommit returns info about the last commit on a branch
-func GetLastCommit(repoPath, branch string) (*CommitInfo, error) {
-	// Format: hash|author|date|msg
-	format := "%h|%an|%ar|%s"
-	// nolint:gosec // Trusted input
-	cmd := exec.Command("git", "-C", repoPath, "log", "-1", fmt.Sprintf("--format=%s", format), branch)
-	output, err := cmd.Output()
+// GetLastCommit returns the last commit on a branch
+func GetLastCommit(repoPath, branch string) (*Commit, error) {
+	output, err := gitCommand(repoPath, "log", "-1", "--format="+commitFormat, branch, "--").Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get log: %w", err)
 	}
 
-	parts := strings.Split(strings.TrimSpace(string(output)), "|")
-	if len(parts) < 4 {
-		return nil, fmt.Errorf("invalid log format")
+	commits := parseCommits(string(output))
+	if len(commits) == 0 {
+		return nil, ErrNotFound
 	}
-
-	return &CommitInfo{
-		Hash:   parts[0],
-		Author: parts[1],
-		Date:   parts[2],
-		Msg:    parts[3],
-	}, nil
+	return &commits[0], nil
 }
 
 // SetHead sets the HEAD symbolic ref (for default branch)
@@ -327,6 +313,15 @@ func SetHead(repoPath, branch string) error {
 	return nil
 }
 
+// ValidBranchName reports whether name is a valid branch name for SetHead
+func ValidBranchName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "-") {
+		return false
+	}
+	// nolint:gosec // The name is passed as a single argument
+	return exec.Command("git", "check-ref-format", "refs/heads/"+name).Run() == nil
+}
+
 // SetUpstream sets the upstream for a branch
 func SetUpstream(repoPath, branch, remote, remoteBranch string) error {
 	// nolint:gosec // Trusted input
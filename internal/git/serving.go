@@ -0,0 +1,139 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ServingConfig is the repository configuration LGH applies to bare
+// repositories so that partial clones, shallow fetches and fetches by
+// object ID work and are served from bitmaps and commit-graphs.
+var ServingConfig = [][2]string{
+	// clone --filter=blob:none / tree:0
+	{"uploadpack.allowFilter", "true"},
+	// Lazy fetches of missing blobs ask for arbitrary object IDs
+	{"uploadpack.allowAnySHA1InWant", "true"},
+	{"uploadpack.allowReachableSHA1InWant", "true"},
+	{"uploadpack.allowTipSHA1InWant", "true"},
+	// Reachability bitmaps and commit-graphs for fast counting and negotiation
+	{"repack.writeBitmaps", "true"},
+	{"pack.writeBitmapHashCache", "true"},
+	{"core.commitGraph", "true"},
+	{"gc.writeCommitGraph", "true"},
+}
+
+// Repack thresholds used by Maintain
+const (
+	maxPacksBeforeRepack = 8
+	maxLooseBeforeRepack = 1000
+)
+
+// ConfigureServing applies ServingConfig to a bare repository
+func ConfigureServing(barePath string) error {
+	for _, kv := range ServingConfig {
+		// nolint:gosec // G204: keys and values are constants
+		cmd := exec.Command("git", "-C", barePath, "config", kv[0], kv[1])
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to set %s: %s, %w", kv[0], strings.TrimSpace(string(output)), err)
+		}
+	}
+	return nil
+}
+
+// Maintain keeps the server-side acceleration data of a bare repository
+// current. The commit-graph is updated incrementally every time; objects are
+// repacked into a single pack with a reachability bitmap only when there is
+// no bitmap yet or packs and loose objects have piled up.
+func Maintain(barePath string) error {
+	// nolint:gosec // G204: barePath is a registered repository
+	cmd := exec.Command("git", "-C", barePath, "commit-graph", "write", "--reachable", "--split")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to write commit-graph: %s, %w", strings.TrimSpace(string(output)), err)
+	}
+
+	packs, loose, err := countObjects(barePath)
+	if err != nil {
+		return err
+	}
+	if packs+loose == 0 {
+		return nil
+	}
+	if hasBitmap(barePath) && packs <= maxPacksBeforeRepack && loose <= maxLooseBeforeRepack {
+		return nil
+	}
+
+	return Repack(barePath)
+}
+
+// Repack packs all objects into one pack with a reachability bitmap and
+// rewrites the commit-graph
+func Repack(barePath string) error {
+	// nolint:gosec // G204: barePath is a registered repository
+	cmd := exec.Command("git", "-C", barePath, "repack", "-a", "-d", "-q", "--write-bitmap-index")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to repack: %s, %w", strings.TrimSpace(string(output)), err)
+	}
+
+	// nolint:gosec // G204: barePath is a registered repository
+	cmd = exec.Command("git", "-C", barePath, "commit-graph", "write", "--reachable")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to write commit-graph: %s, %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// countObjects returns the number of packs and loose objects
+func countObjects(barePath string) (packs, loose int, err error) {
+	// nolint:gosec // G204: barePath is a registered repository
+	output, err := exec.Command("git", "-C", barePath, "count-objects", "-v").Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count objects: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ": ")
+		if !ok {
+			continue
+		}
+		n, _ := strconv.Atoi(value)
+		switch key {
+		case "count":
+			loose = n
+		case "packs":
+			packs = n
+		}
+	}
+	return packs, loose, nil
+}
+
+// hasBitmap reports whether the repository has a pack bitmap
+func hasBitmap(barePath string) bool {
+	matches, _ := filepath.Glob(filepath.Join(barePath, "objects", "pack", "*.bitmap"))
+	return len(matches) > 0
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInitBareRepoServingConfig(t *testing.T) {
+	barePath := filepath.Join(t.TempDir(), "repo.git")
+	if err := InitBareRepo(barePath); err != nil {
+		t.Fatalf("InitBareRepo: %v", err)
+	}
+
+	for _, kv := range ServingConfig {
+		out, err := exec.Command("git", "-C", barePath, "config", kv[0]).Output()
+		if err != nil || strings.TrimSpace(string(out)) != kv[1] {
+			t.Errorf("%s = %q, want %q", kv[0], strings.TrimSpace(string(out)), kv[1])
+		}
+	}
+
+	// Maintenance of an empty repository is a no-op
+	if err := Maintain(barePath); err != nil {
+		t.Fatalf("Maintain on empty repo: %v", err)
+	}
+}
+
+func TestMaintainWritesBitmap(t *testing.T) {
+	dir := t.TempDir()
+	barePath := filepath.Join(dir, "repo.git")
+	if err := InitBareRepo(barePath); err != nil {
+		t.Fatalf("InitBareRepo: %v", err)
+	}
+
+	work := filepath.Join(dir, "work")
+	for _, args := range [][]string{
+		{"init", "-q", work},
+		{"-C", work, "-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"-C", work, "push", "-q", barePath, "HEAD:refs/heads/main"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+
+	if err := Maintain(barePath); err != nil {
+		t.Fatalf("Maintain: %v", err)
+	}
+	if !hasBitmap(barePath) {
+		t.Error("expected a pack bitmap after maintenance")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
@@ -95,6 +96,51 @@ func (s *StatusStore) Get(repo, commitSHA string) (*CommitStatusReport, error) {
 	return s.readReport(statusFile)
 }
 
+// Rename moves the statuses of a renamed repository to its new name
+func (s *StatusStore) Rename(oldRepo, newRepo string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	oldDir := filepath.Join(s.dataDir, sanitizeRepoName(oldRepo))
+	newDir := filepath.Join(s.dataDir, sanitizeRepoName(newRepo))
+	files, err := filepath.Glob(filepath.Join(oldDir, "*.json"))
+	if err != nil || len(files) == 0 {
+		return err
+	}
+
+	if err := os.MkdirAll(newDir, 0755); err != nil {
+		return fmt.Errorf("failed to create status directory: %w", err)
+	}
+	// Move files only: the directory may also hold nested namespaces
+	for _, file := range files {
+		if err := os.Rename(file, filepath.Join(newDir, filepath.Base(file))); err != nil {
+			return fmt.Errorf("failed to move status: %w", err)
+		}
+	}
+	_ = os.Remove(oldDir)
+	return nil
+}
+
+// Delete removes the statuses of a purged repository
+func (s *StatusStore) Delete(repo string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repoDir := filepath.Join(s.dataDir, sanitizeRepoName(repo))
+	files, err := filepath.Glob(filepath.Join(repoDir, "*.json"))
+	if err != nil {
+		return err
+	}
+	// Delete files only: the directory may also hold nested namespaces
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			return fmt.Errorf("failed to delete status: %w", err)
+		}
+	}
+	_ = os.Remove(repoDir)
+	return nil
+}
+
 func (s *StatusStore) readReport(path string) (*CommitStatusReport, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -117,10 +163,16 @@ func (s *StatusStore) writeReport(path string, report *CommitStatusReport) error
 
 func sanitizeRepoName(repo string) string {
 	// Remove .git suffix if present and sanitize
-	name := repo
-	if len(name) > 4 && name[len(name)-4:] == ".git" {
-		name = name[:len(name)-4]
+	name := strings.TrimSuffix(repo, ".git")
+	// Namespaces become subdirectories: team/app -> statuses/team/app
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = sanitizeSegment(segment)
 	}
+	return filepath.Join(segments...)
+}
+
+func sanitizeSegment(name string) string {
 	// Replace any problematic characters
 	result := make([]byte, 0, len(name))
 	for _, c := range []byte(name) {
@@ -0,0 +1,201 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package hooks implements the checks run by LGH's managed git hooks.
+// The hooks call back into the lgh binary ('lgh hook <name>'), which runs
+// inside the bare repository with the pusher in REMOTE_USER.
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/event"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/ignore"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/server"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+)
+
+// ErrRejected is returned when a hook refuses a push. The reasons have
+// already been written for the git client.
+var ErrRejected = errors.New("push rejected")
+
+// Rejection explains why a ref update was refused
+type Rejection struct {
+	Ref    string
+	Reason string
+}
+
+// Pusher returns the identity the server passed to git
+func Pusher() string {
+	if name := os.Getenv("REMOTE_USER"); name != "" {
+		return name
+	}
+	return git.DefaultRemoteUser
+}
+
+// PreReceive checks the ref updates read from stdin for the bare repository
+// at barePath against its branch protection rules and scan policy.
+// Findings are written to stderr, which git relays to the client as
+// "remote:" lines; a rejection makes PreReceive return ErrRejected.
+func PreReceive(barePath string, stdin io.Reader, stderr io.Writer) error {
+	updates, err := git.ParseRefUpdates(stdin)
+	if err != nil {
+		return err
+	}
+
+	repo, err := registry.New().FindByBarePath(barePath)
+	if errors.Is(err, registry.ErrNotFound) {
+		// Not a registered repository: nothing to enforce
+		return nil
+	}
+	if err != nil {
+		// SECURITY: Without the registry the rules are unknown; fail closed
+		fmt.Fprintf(stderr, "LGH: push rejected: cannot read repository settings: %v\n", err)
+		return ErrRejected
+	}
+
+	pusher := Pusher()
+	var groups []string
+	if u, err := users.NewStore().Find(pusher); err == nil {
+		groups = u.Groups
+	}
+
+	rejections := CheckProtection(barePath, repo, pusher, groups, updates)
+	if len(rejections) > 0 {
+		fmt.Fprintln(stderr, "LGH: push rejected by branch protection")
+		reasons := make([]string, 0, len(rejections))
+		for _, r := range rejections {
+			fmt.Fprintf(stderr, "  %s: %s\n", r.Ref, r.Reason)
+			reasons = append(reasons, r.Ref+": "+r.Reason)
+		}
+		publishRejected(barePath, map[string]interface{}{
+			"reason":  "branch_protection",
+			"pusher":  pusher,
+			"refs":    refNames(updates),
+			"details": reasons,
+		})
+		return ErrRejected
+	}
+
+	return checkContent(barePath, repo, pusher, updates, stderr)
+}
+
+// checkContent runs the push scan according to the repository's scan policy
+func checkContent(barePath string, repo *registry.RepoMapping, pusher string, updates []git.RefUpdate, stderr io.Writer) error {
+	policy := repo.EffectiveScanPolicy()
+	if policy == registry.ScanOff {
+		return nil
+	}
+
+	report, err := ScanPush(barePath, updates)
+	if err != nil {
+		if policy == registry.ScanWarn {
+			fmt.Fprintf(stderr, "LGH: warning: push scan failed: %v\n", err)
+			return nil
+		}
+		fmt.Fprintf(stderr, "LGH: push rejected: scan failed: %v\n", err)
+		return ErrRejected
+	}
+	if len(report.Items) == 0 {
+		return nil
+	}
+
+	if policy == registry.ScanWarn || !report.HasBlocking {
+		fmt.Fprintln(stderr, "LGH: warning: this push contains files that should not be committed")
+		printFindings(stderr, report)
+		return nil
+	}
+
+	fmt.Fprintln(stderr, "LGH: push rejected: it contains files that should not be committed")
+	printFindings(stderr, report)
+	fmt.Fprintln(stderr, "Remove them from the pushed commits and push again.")
+
+	paths := []string{}
+	for _, item := range report.Items {
+		if item.Blocking {
+			paths = append(paths, item.Path)
+		}
+	}
+	publishRejected(barePath, map[string]interface{}{
+		"reason": "content_scan",
+		"pusher": pusher,
+		"refs":   refNames(updates),
+		"paths":  paths,
+	})
+	return ErrRejected
+}
+
+// printFindings writes scan findings for the git client
+func printFindings(w io.Writer, report *ignore.TrashReport) {
+	for _, item := range report.Items {
+		switch {
+		case item.Path == "":
+			fmt.Fprintf(w, "  %s\n", item.Message)
+		case item.Size > 0:
+			fmt.Fprintf(w, "  %s: %s (%s)\n", item.Path, item.Message, ignore.FormatHumanSize(item.Size))
+		default:
+			fmt.Fprintf(w, "  %s: %s\n", item.Path, item.Message)
+		}
+	}
+}
+
+// publishRejected emits a git.push.rejected event through the running
+// server, or to the local event log if the server cannot be reached.
+func publishRejected(barePath string, payload map[string]interface{}) {
+	repoName, err := RepoName(config.Get().ReposDir, barePath)
+	if err != nil {
+		repoName = strings.TrimSuffix(filepath.Base(barePath), ".git")
+	}
+	err = server.SendIPCMessage(server.IPCMessage{
+		Kind:    server.IPCKindPublish,
+		Type:    event.GitPushRejected,
+		Repo:    repoName,
+		Payload: payload,
+	})
+	if err != nil {
+		event.Publish(event.GitPushRejected, repoName, payload)
+	}
+}
+
+// refNames returns the refs touched by a push
+func refNames(updates []git.RefUpdate) []string {
+	refs := make([]string, 0, len(updates))
+	for _, u := range updates {
+		refs = append(refs, u.Ref)
+	}
+	return refs
+}
+
+// BarePathFromEnv returns the bare repository a hook is running in
+func BarePathFromEnv() (string, error) {
+	dir := os.Getenv("GIT_DIR")
+	if dir == "" {
+		dir = "."
+	}
+	return filepath.Abs(dir)
+}
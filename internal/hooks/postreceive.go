@@ -0,0 +1,73 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/server"
+)
+
+// RepoName returns the name events use for the bare repository at barePath:
+// its path below reposDir without ".git", e.g. "team/api"
+func RepoName(reposDir, barePath string) (string, error) {
+	rel, err := filepath.Rel(reposDir, barePath)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("repository %s is outside the repos directory", barePath)
+	}
+	return strings.TrimSuffix(filepath.ToSlash(rel), ".git"), nil
+}
+
+// PostReceive reports the ref updates read from stdin to the running server,
+// which publishes them as git.push and git.tag events. If the server cannot
+// be reached the events go to the local event log instead.
+func PostReceive(barePath string, stdin io.Reader) error {
+	updates, err := git.ParseRefUpdates(stdin)
+	if err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	repoName, err := RepoName(config.Get().ReposDir, barePath)
+	if err != nil {
+		return err
+	}
+
+	msg := server.IPCMessage{
+		Kind:    server.IPCKindPostReceive,
+		Repo:    repoName,
+		PushID:  os.Getenv(git.PushIDEnv),
+		Pusher:  Pusher(),
+		Updates: updates,
+	}
+	if err := server.SendIPCMessage(msg); err != nil {
+		git.PublishRefUpdates(barePath, repoName, msg.Pusher, msg.PushID, updates)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package hooks
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRepoName(t *testing.T) {
+	reposDir := filepath.Join(t.TempDir(), "repos")
+	tests := []struct {
+		barePath string
+		want     string
+	}{
+		{filepath.Join(reposDir, "app.git"), "app"},
+		{filepath.Join(reposDir, "team", "api.git"), "team/api"},
+		{filepath.Join(reposDir, "team", "web", "ui.git"), "team/web/ui"},
+	}
+	for _, tt := range tests {
+		if got, err := RepoName(reposDir, tt.barePath); err != nil || got != tt.want {
+			t.Errorf("RepoName(%s) = %q, %v; want %q", tt.barePath, got, err, tt.want)
+		}
+	}
+
+	for _, outside := range []string{reposDir, filepath.Join(filepath.Dir(reposDir), "other.git")} {
+		if _, err := RepoName(reposDir, outside); err == nil {
+			t.Errorf("RepoName(%s) should fail outside the repos directory", outside)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package hooks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+// CheckProtection checks ref updates against the repository's branch
+// protection rules and returns one rejection per refused update.
+func CheckProtection(barePath string, repo *registry.RepoMapping, pusher string, groups []string, updates []git.RefUpdate) []Rejection {
+	var rejections []Rejection
+	for _, u := range updates {
+		branch, ok := strings.CutPrefix(u.Ref, "refs/heads/")
+		if !ok {
+			continue
+		}
+		for _, rule := range repo.ProtectionFor(branch) {
+			if reason := checkRule(barePath, rule, pusher, groups, u); reason != "" {
+				rejections = append(rejections, Rejection{
+					Ref:    u.Ref,
+					Reason: fmt.Sprintf("%s (protected branch '%s')", reason, rule.Branch),
+				})
+				break
+			}
+		}
+	}
+	return rejections
+}
+
+// checkRule returns why the update violates the rule, or "" if it does not
+func checkRule(barePath string, rule registry.BranchProtection, pusher string, groups []string, u git.RefUpdate) string {
+	if !rule.AllowsPusher(pusher, groups) {
+		return fmt.Sprintf("'%s' is not allowed to push", pusher)
+	}
+
+	switch u.Action() {
+	case "deleted":
+		if rule.NoDeletion {
+			return "deletion is not allowed"
+		}
+		return ""
+	case "updated":
+		if rule.NoForcePush {
+			ff, err := git.IsAncestor(barePath, u.Old, u.New)
+			if err != nil {
+				return fmt.Sprintf("cannot verify fast-forward: %v", err)
+			}
+			if !ff {
+				return "force-push is not allowed"
+			}
+		}
+	}
+
+	if rule.LinearHistory {
+		merges, err := git.MergeCommits(barePath, u.Old, u.New)
+		if err != nil {
+			return fmt.Sprintf("cannot verify linear history: %v", err)
+		}
+		if len(merges) > 0 {
+			return fmt.Sprintf("merge commits are not allowed (%s)", shortHash(merges[0]))
+		}
+	}
+
+	return ""
+}
+
+// shortHash abbreviates an object name for messages
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package hooks
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+// gitRun runs git in dir and returns its trimmed output
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(cmd.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestCheckProtection(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	gitRun(t, dir, "init", "-q", "-b", "main")
+	gitRun(t, dir, "commit", "-q", "--allow-empty", "-m", "base")
+	base := gitRun(t, dir, "rev-parse", "HEAD")
+	gitRun(t, dir, "commit", "-q", "--allow-empty", "-m", "next")
+	next := gitRun(t, dir, "rev-parse", "HEAD")
+	gitRun(t, dir, "checkout", "-q", "-b", "side", base)
+	gitRun(t, dir, "commit", "-q", "--allow-empty", "-m", "side")
+	side := gitRun(t, dir, "rev-parse", "HEAD")
+	gitRun(t, dir, "merge", "-q", "--no-ff", "-m", "merge", next)
+	merge := gitRun(t, dir, "rev-parse", "HEAD")
+
+	repo := &registry.RepoMapping{
+		Name: "app",
+		Protections: []registry.BranchProtection{
+			{Branch: "main", NoForcePush: true, NoDeletion: true, LinearHistory: true},
+			{Branch: "release/*", AllowedPushers: []string{"group:leads"}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		pusher string
+		groups []string
+		update git.RefUpdate
+		reason string
+	}{
+		{"fast-forward", "alice", nil, git.RefUpdate{Old: base, New: next, Ref: "refs/heads/main"}, ""},
+		{"force-push", "alice", nil, git.RefUpdate{Old: next, New: side, Ref: "refs/heads/main"}, "force-push"},
+		{"deletion", "alice", nil, git.RefUpdate{Old: next, New: git.ZeroHash, Ref: "refs/heads/main"}, "deletion"},
+		{"merge commit", "alice", nil, git.RefUpdate{Old: next, New: merge, Ref: "refs/heads/main"}, "merge commits"},
+		{"unprotected branch", "alice", nil, git.RefUpdate{Old: next, New: side, Ref: "refs/heads/topic"}, ""},
+		{"tag", "alice", nil, git.RefUpdate{Old: git.ZeroHash, New: next, Ref: "refs/tags/main"}, ""},
+		{"pusher not allowed", "alice", nil, git.RefUpdate{Old: git.ZeroHash, New: next, Ref: "refs/heads/release/1.0"}, "not allowed to push"},
+		{"pusher in group", "bob", []string{"leads"}, git.RefUpdate{Old: git.ZeroHash, New: next, Ref: "refs/heads/release/1.0"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rejections := CheckProtection(dir, repo, tt.pusher, tt.groups, []git.RefUpdate{tt.update})
+			if tt.reason == "" {
+				if len(rejections) != 0 {
+					t.Errorf("unexpected rejection: %+v", rejections)
+				}
+				return
+			}
+			if len(rejections) != 1 || !strings.Contains(rejections[0].Reason, tt.reason) {
+				t.Errorf("rejections = %+v, want reason containing %q", rejections, tt.reason)
+			}
+		})
+	}
+}
+
+func TestPreReceiveRegistryError(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	barePath := filepath.Join(home, "app.git")
+	if err := os.MkdirAll(config.GetLGHDir(), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unregistered repositories have nothing to enforce
+	if err := PreReceive(barePath, strings.NewReader(""), io.Discard); err != nil {
+		t.Errorf("PreReceive() for an unregistered repository = %v, want nil", err)
+	}
+
+	// An unreadable registry must not switch the checks off
+	if err := os.WriteFile(config.GetMappingsPath(), []byte("repos: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := PreReceive(barePath, strings.NewReader(""), io.Discard); !errors.Is(err, ErrRejected) {
+		t.Errorf("PreReceive() with a broken registry = %v, want ErrRejected", err)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package hooks
+
+import (
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/ignore"
+)
+
+// ScanPush applies the same trash rules as 'lgh up' (sensitive files,
+// dangerous directories, size limits) to the files a push introduces.
+func ScanPush(barePath string, updates []git.RefUpdate) (*ignore.TrashReport, error) {
+	var commits []string
+	for _, u := range updates {
+		if u.New != git.ZeroHash {
+			commits = append(commits, u.New)
+		}
+	}
+
+	blobs, err := git.NewBlobs(barePath, commits)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]ignore.FileEntry, 0, len(blobs))
+	for _, b := range blobs {
+		entries = append(entries, ignore.FileEntry{Path: b.Path, Size: b.Size})
+	}
+	return ignore.CheckFiles(entries), nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/ignore"
+)
+
+func TestScanPush(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	gitRun(t, dir, "init", "-q", "-b", "main")
+	gitRun(t, dir, "commit", "-q", "--allow-empty", "-m", "base")
+	base := gitRun(t, dir, "rev-parse", "HEAD")
+
+	if err := os.MkdirAll(filepath.Join(dir, "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"README.md": "hello", "config/.env": "SECRET=1"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitRun(t, dir, "add", "-f", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "files")
+	pushed := gitRun(t, dir, "rev-parse", "HEAD")
+
+	// Simulate pre-receive: the pushed commit is not referenced yet
+	gitRun(t, dir, "update-ref", "refs/heads/main", base)
+
+	report, err := ScanPush(dir, []git.RefUpdate{{Old: base, New: pushed, Ref: "refs/heads/main"}})
+	if err != nil {
+		t.Fatalf("ScanPush failed: %v", err)
+	}
+	if !report.HasBlocking {
+		t.Fatal("expected blocking findings")
+	}
+	if len(report.Items) != 1 || report.Items[0].Path != "config/.env" || report.Items[0].Type != ignore.TrashTypeSensitiveFile {
+		t.Errorf("items = %+v, want only config/.env", report.Items)
+	}
+	if report.TotalSize != int64(len("hello")+len("SECRET=1")) {
+		t.Errorf("TotalSize = %d", report.TotalSize)
+	}
+
+	// Deletions introduce no files
+	report, err = ScanPush(dir, []git.RefUpdate{{Old: base, New: git.ZeroHash, Ref: "refs/heads/main"}})
+	if err != nil || len(report.Items) != 0 {
+		t.Errorf("deletion: items = %+v, err = %v", report.Items, err)
+	}
+}
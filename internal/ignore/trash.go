@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 )
@@ -93,12 +94,15 @@ var DangerousDirectories = []string{
 	"__pycache__",
 }
 
+// FileEntry is a file to check: a path relative to the repository root
+// (with '/' separators, as git prints them) and its size
+type FileEntry struct {
+	Path string
+	Size int64
+}
+
 // DetectTrash scans a directory for potential issues using git ls-files
 func DetectTrash(dir string) (*TrashReport, error) {
-	report := &TrashReport{
-		Items: []TrashItem{},
-	}
-
 	// Use git ls-files to get list of tracked and untracked (but not ignored) files
 	// -c: cached (tracked)
 	// -o: others (untracked)
@@ -115,7 +119,9 @@ func DetectTrash(dir string) (*TrashReport, error) {
 		return nil, fmt.Errorf("git ls-files failed: %v", err)
 	}
 
+	var entries []FileEntry
 	files := strings.Split(string(output), "\x00")
+	lfsFiles := lfsTracked(dir, files)
 	for _, relPath := range files {
 		if relPath == "" {
 			continue
@@ -131,8 +137,30 @@ func DetectTrash(dir string) (*TrashReport, error) {
 			continue
 		}
 
+		size := info.Size()
+		if lfsFiles[relPath] {
+			// Git stores a small pointer; the content goes to the LFS server
+			size = 0
+		}
+		entries = append(entries, FileEntry{Path: relPath, Size: size})
+	}
+
+	return CheckFiles(entries), nil
+}
+
+// CheckFiles applies the trash rules (dangerous directories, sensitive files,
+// file and total size limits) to a list of files. It is shared by the
+// client-side check in 'lgh up' and the server-side push scan.
+func CheckFiles(entries []FileEntry) *TrashReport {
+	report := &TrashReport{
+		Items: []TrashItem{},
+	}
+
+	for _, entry := range entries {
+		relPath := entry.Path
+
 		// Check for dangerous directories (path components)
-		parts := strings.Split(relPath, string(os.PathSeparator))
+		parts := strings.Split(filepath.ToSlash(relPath), "/")
 		for _, part := range parts {
 			if isDangerousDir(part) {
 				report.Items = append(report.Items, TrashItem{
@@ -149,28 +177,28 @@ func DetectTrash(dir string) (*TrashReport, error) {
 		}
 
 		// Check for sensitive files
-		if isSensitiveFile(filepath.Base(relPath)) {
+		if isSensitiveFile(path.Base(filepath.ToSlash(relPath))) {
 			report.Items = append(report.Items, TrashItem{
 				Type:     TrashTypeSensitiveFile,
 				Path:     relPath,
-				Size:     info.Size(),
+				Size:     entry.Size,
 				Message:  "Sensitive file detected",
 				Blocking: true,
 			})
 		}
 
 		// Check for large files
-		if info.Size() > MaxSingleFileSize {
+		if entry.Size > MaxSingleFileSize {
 			report.Items = append(report.Items, TrashItem{
 				Type:     TrashTypeLargeFile,
 				Path:     relPath,
-				Size:     info.Size(),
+				Size:     entry.Size,
 				Message:  fmt.Sprintf("File exceeds %dMB limit", MaxSingleFileSize/(1024*1024)),
 				Blocking: true,
 			})
 		}
 
-		report.TotalSize += info.Size()
+		report.TotalSize += entry.Size
 	}
 
 	// Check total size
@@ -191,7 +219,7 @@ func DetectTrash(dir string) (*TrashReport, error) {
 		}
 	}
 
-	return report, nil
+	return report
 }
 
 // isDangerousDir checks if a directory name is dangerous
@@ -224,6 +252,60 @@ func isSensitiveFile(name string) bool {
 	return false
 }
 
+// lfsTracked returns the paths whose filter attribute is "lfs"
+func lfsTracked(dir string, paths []string) map[string]bool {
+	tracked := map[string]bool{}
+
+	cmd := exec.Command("git", "check-attr", "-z", "--stdin", "filter")
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00"))
+	output, err := cmd.Output()
+	if err != nil {
+		return tracked
+	}
+
+	// Output is "<path> NUL filter NUL <value> NUL" per path
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		if fields[i+2] == "lfs" {
+			tracked[fields[i]] = true
+		}
+	}
+	return tracked
+}
+
+// BlocksBesidesLargeFiles reports whether the report blocks a push for
+// reasons Git LFS does not solve
+func (r *TrashReport) BlocksBesidesLargeFiles() bool {
+	for _, item := range r.Items {
+		if item.Blocking && item.Type != TrashTypeLargeFile {
+			return true
+		}
+	}
+	return false
+}
+
+// LFSTrackPatterns returns "git lfs track" patterns covering the large files
+// of a report: "*.<ext>" for files with an extension, the path otherwise
+func LFSTrackPatterns(report *TrashReport) []string {
+	seen := map[string]bool{}
+	var patterns []string
+	for _, item := range report.Items {
+		if item.Type != TrashTypeLargeFile {
+			continue
+		}
+		pattern := item.Path
+		if ext := path.Ext(filepath.ToSlash(item.Path)); ext != "" {
+			pattern = "*" + ext
+		}
+		if !seen[pattern] {
+			seen[pattern] = true
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
 // FormatHumanSize formats bytes to human readable string
 func FormatHumanSize(bytes int64) string {
 	const unit = 1024
@@ -225,6 +225,60 @@ func TestTrashReportHasBlocking(t *testing.T) {
 	}
 }
 
+// ---- CheckFiles ----
+
+func TestCheckFiles(t *testing.T) {
+	report := CheckFiles([]FileEntry{
+		{Path: "src/main.go", Size: 100},
+		{Path: "deploy/prod.pem", Size: 10},
+		{Path: "web/node_modules/x/index.js", Size: 10},
+		{Path: "data/dump.bin", Size: MaxSingleFileSize + 1},
+	})
+
+	found := map[TrashType]string{}
+	for _, item := range report.Items {
+		found[item.Type] = item.Path
+	}
+	if found[TrashTypeSensitiveFile] != "deploy/prod.pem" {
+		t.Errorf("sensitive file not detected: %+v", report.Items)
+	}
+	if found[TrashTypeNodeModules] != "web/node_modules/x/index.js" {
+		t.Errorf("node_modules not detected: %+v", report.Items)
+	}
+	if found[TrashTypeLargeFile] != "data/dump.bin" {
+		t.Errorf("large file not detected: %+v", report.Items)
+	}
+	if !report.HasBlocking {
+		t.Error("expected blocking findings")
+	}
+	if report.TotalSize != 120+MaxSingleFileSize+1 {
+		t.Errorf("TotalSize = %d", report.TotalSize)
+	}
+
+	if clean := CheckFiles([]FileEntry{{Path: "README.md", Size: 5}}); len(clean.Items) != 0 || clean.HasBlocking {
+		t.Errorf("clean files reported: %+v", clean.Items)
+	}
+}
+
+func TestBlocksBesidesLargeFiles(t *testing.T) {
+	// Large files alone get a Git LFS suggestion, not a blocked push
+	largeOnly := CheckFiles([]FileEntry{{Path: "data/dump.bin", Size: MaxSingleFileSize + 1}})
+	if !largeOnly.HasBlocking || largeOnly.BlocksBesidesLargeFiles() {
+		t.Errorf("large file only: HasBlocking = %v, BlocksBesidesLargeFiles = %v", largeOnly.HasBlocking, largeOnly.BlocksBesidesLargeFiles())
+	}
+	if patterns := LFSTrackPatterns(largeOnly); len(patterns) != 1 || patterns[0] != "*.bin" {
+		t.Errorf("LFSTrackPatterns = %v, want [*.bin]", patterns)
+	}
+
+	withKey := CheckFiles([]FileEntry{
+		{Path: "data/dump.bin", Size: MaxSingleFileSize + 1},
+		{Path: "deploy/prod.pem", Size: 10},
+	})
+	if !withKey.BlocksBesidesLargeFiles() {
+		t.Error("a sensitive file must still block the push")
+	}
+}
+
 // ---- DetectTrash ----
 
 func TestDetectTrashSensitiveFile(t *testing.T) {
@@ -305,6 +359,41 @@ func TestDetectTrashLargeFile(t *testing.T) {
 	}
 }
 
+func TestDetectTrashLFSTrackedFile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping DetectTrash test in short mode - requires git repo")
+	}
+
+	tmpDir := t.TempDir()
+	exec.Command("git", "init", tmpDir).Run()
+
+	// Files tracked by Git LFS are pushed as pointers
+	os.WriteFile(filepath.Join(tmpDir, ".gitattributes"), []byte("*.psd filter=lfs diff=lfs merge=lfs -text\n"), 0644)
+	data := make([]byte, 51*1024*1024)
+	os.WriteFile(filepath.Join(tmpDir, "design.psd"), data, 0644)
+	os.WriteFile(filepath.Join(tmpDir, "large.bin"), data, 0644)
+
+	report, err := DetectTrash(tmpDir)
+	if err != nil {
+		t.Fatalf("DetectTrash failed: %v", err)
+	}
+
+	var large []string
+	for _, item := range report.Items {
+		if item.Type == TrashTypeLargeFile {
+			large = append(large, item.Path)
+		}
+	}
+	if len(large) != 1 || large[0] != "large.bin" {
+		t.Errorf("expected only large.bin to be flagged, got %v", large)
+	}
+
+	patterns := LFSTrackPatterns(report)
+	if len(patterns) != 1 || patterns[0] != "*.bin" {
+		t.Errorf("expected [*.bin], got %v", patterns)
+	}
+}
+
 func TestDetectTrashClean(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping DetectTrash test in short mode - requires git repo")
@@ -0,0 +1,250 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package keys manages the SSH public keys accepted by the LGH SSH server
+package keys
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+// authorizedKeysFile is the key file inside the SSH directory
+const authorizedKeysFile = "authorized_keys"
+
+// fileHeader is written at the top of the authorized_keys file
+const fileHeader = "# Managed by LGH: use 'lgh key add/list/remove'.\n" +
+	"# Format: [user=\"<lgh user>\",]name=\"<name>\",added=\"<RFC3339>\" <key> [comment]\n"
+
+// Key is an authorized SSH public key. Keys without a User act as the
+// server owner, like tokens without a user.
+type Key struct {
+	Name      string
+	User      string
+	PublicKey ssh.PublicKey
+	Comment   string
+	AddedAt   time.Time
+}
+
+// Fingerprint returns the SHA256 fingerprint of the key
+func (k *Key) Fingerprint() string {
+	return ssh.FingerprintSHA256(k.PublicKey)
+}
+
+// Identity returns the name the key authenticates as
+func (k *Key) Identity() string {
+	if k.User != "" {
+		return k.User
+	}
+	return "key-" + k.Name
+}
+
+// Store manages the authorized_keys file
+type Store struct {
+	path string
+	mu   sync.RWMutex
+}
+
+// NewStore creates a Store for the default SSH directory
+func NewStore() *Store {
+	return NewStoreWithPath(filepath.Join(config.GetSSHDir(), authorizedKeysFile))
+}
+
+// NewStoreWithPath creates a Store for a specific authorized_keys file
+func NewStoreWithPath(path string) *Store {
+	return &Store{path: path}
+}
+
+// ParsePublicKey parses one public key in authorized_keys format
+// (e.g. the contents of ~/.ssh/id_ed25519.pub)
+func ParsePublicKey(data []byte) (ssh.PublicKey, string, error) {
+	key, comment, _, _, err := ssh.ParseAuthorizedKey(bytes.TrimSpace(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid SSH public key: %w", err)
+	}
+	return key, comment, nil
+}
+
+// load reads the key file with file locking
+func (s *Store) load() ([]Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// The lock file lives next to the key file
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	fileLock := registry.NewFileLock(s.path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fileLock.Unlock()
+
+	// nolint:gosec // G304: path is internally constructed and trusted
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Key{}, nil
+		}
+		return nil, fmt.Errorf("failed to read authorized keys: %w", err)
+	}
+
+	list := []Key{}
+	for len(bytes.TrimSpace(data)) > 0 {
+		pub, comment, options, rest, err := ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			// Only comments and blank lines are left
+			break
+		}
+		key := Key{PublicKey: pub, Comment: comment}
+		for _, opt := range options {
+			name, value, _ := strings.Cut(opt, "=")
+			value = strings.Trim(value, `"`)
+			switch name {
+			case "user":
+				key.User = value
+			case "name":
+				key.Name = value
+			case "added":
+				key.AddedAt, _ = time.Parse(time.RFC3339, value)
+			}
+		}
+		list = append(list, key)
+		data = rest
+	}
+	return list, nil
+}
+
+// save writes the key file with file locking
+func (s *Store) save(list []Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	fileLock := registry.NewFileLock(s.path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fileLock.Unlock()
+
+	var buf bytes.Buffer
+	buf.WriteString(fileHeader)
+	for _, k := range list {
+		var options []string
+		if k.User != "" {
+			options = append(options, fmt.Sprintf("user=%q", k.User))
+		}
+		options = append(options, fmt.Sprintf("name=%q", k.Name))
+		if !k.AddedAt.IsZero() {
+			options = append(options, fmt.Sprintf("added=%q", k.AddedAt.UTC().Format(time.RFC3339)))
+		}
+		line := strings.Join(options, ",") + " " + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(k.PublicKey)))
+		if k.Comment != "" {
+			line += " " + k.Comment
+		}
+		buf.WriteString(line + "\n")
+	}
+
+	if err := os.WriteFile(s.path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write authorized keys: %w", err)
+	}
+	return nil
+}
+
+// Add stores a key. Names and keys must be unique.
+func (s *Store) Add(key Key) error {
+	if key.Name == "" || strings.ContainsAny(key.Name, "\",\n") {
+		return fmt.Errorf("invalid key name '%s'", key.Name)
+	}
+
+	list, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	fingerprint := key.Fingerprint()
+	for _, k := range list {
+		if k.Name == key.Name {
+			return fmt.Errorf("a key named '%s' already exists", key.Name)
+		}
+		if k.Fingerprint() == fingerprint {
+			return fmt.Errorf("key %s is already added as '%s'", fingerprint, k.Name)
+		}
+	}
+
+	if key.AddedAt.IsZero() {
+		key.AddedAt = time.Now()
+	}
+	return s.save(append(list, key))
+}
+
+// Remove deletes a key by name or fingerprint
+func (s *Store) Remove(nameOrFingerprint string) error {
+	list, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	kept := []Key{}
+	for _, k := range list {
+		if k.Name != nameOrFingerprint && k.Fingerprint() != nameOrFingerprint {
+			kept = append(kept, k)
+		}
+	}
+	if len(kept) == len(list) {
+		return fmt.Errorf("key '%s' not found", nameOrFingerprint)
+	}
+	return s.save(kept)
+}
+
+// List returns all keys
+func (s *Store) List() ([]Key, error) {
+	return s.load()
+}
+
+// Find returns the stored key matching pub
+func (s *Store) Find(pub ssh.PublicKey) (*Key, error) {
+	list, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	want := pub.Marshal()
+	for i := range list {
+		if bytes.Equal(list[i].PublicKey.Marshal(), want) {
+			return &list[i], nil
+		}
+	}
+	return nil, fmt.Errorf("key not authorized")
+}
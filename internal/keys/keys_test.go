@@ -0,0 +1,105 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package keys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func newKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	store := NewStoreWithPath(filepath.Join(t.TempDir(), "authorized_keys"))
+	alice, owner := newKey(t), newKey(t)
+
+	if err := store.Add(Key{Name: "alice-laptop", User: "alice", PublicKey: alice, Comment: "alice@laptop"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := store.Add(Key{Name: "ci", PublicKey: owner}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	if err := store.Add(Key{Name: "again", PublicKey: alice}); err == nil {
+		t.Error("expected duplicate key to be refused")
+	}
+	if err := store.Add(Key{Name: "ci", PublicKey: newKey(t)}); err == nil {
+		t.Error("expected duplicate name to be refused")
+	}
+
+	found, err := store.Find(alice)
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if found.Name != "alice-laptop" || found.User != "alice" || found.Comment != "alice@laptop" || found.AddedAt.IsZero() {
+		t.Errorf("unexpected key: %+v", found)
+	}
+	if found.Identity() != "alice" {
+		t.Errorf("Identity = %s, want alice", found.Identity())
+	}
+
+	ownerKey, err := store.Find(owner)
+	if err != nil || ownerKey.Identity() != "key-ci" {
+		t.Errorf("owner key: %+v, %v", ownerKey, err)
+	}
+
+	if err := store.Remove(found.Fingerprint()); err != nil {
+		t.Fatalf("Remove by fingerprint: %v", err)
+	}
+	if _, err := store.Find(alice); err == nil {
+		t.Error("removed key is still authorized")
+	}
+	if err := store.Remove("alice-laptop"); err == nil {
+		t.Error("expected removing a missing key to fail")
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	line := ssh.MarshalAuthorizedKey(newKey(t))
+	line = append(line[:len(line)-1], []byte(" bob@desk\n")...)
+
+	_, comment, err := ParsePublicKey(line)
+	if err != nil {
+		t.Fatalf("ParsePublicKey: %v", err)
+	}
+	if comment != "bob@desk" {
+		t.Errorf("comment = %q", comment)
+	}
+
+	if _, _, err := ParsePublicKey([]byte("not a key")); err == nil {
+		t.Error("expected invalid key to fail")
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package lfs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+// MediaType is the content type of LFS API requests and responses
+const MediaType = "application/vnd.git-lfs+json"
+
+// maxBatchBody bounds the batch request body (JSON list of object IDs)
+const maxBatchBody = 10 << 20
+
+// pathPattern matches /{repo}.git/info/lfs/{rest}; repo may include a namespace
+var pathPattern = regexp.MustCompile(`^/(.+?)\.git/info/lfs/(.*)$`)
+
+// BatchRequest is the body of POST /info/lfs/objects/batch
+type BatchRequest struct {
+	Operation string   `json:"operation"`
+	Transfers []string `json:"transfers,omitempty"`
+	Objects   []Object `json:"objects"`
+}
+
+// Object is an object in a batch request
+type Object struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+// BatchResponse is the body of a batch response
+type BatchResponse struct {
+	Transfer string           `json:"transfer"`
+	Objects  []ObjectResponse `json:"objects"`
+	HashAlgo string           `json:"hash_algo"`
+}
+
+// ObjectResponse describes how to transfer one object
+type ObjectResponse struct {
+	OID           string             `json:"oid"`
+	Size          int64              `json:"size"`
+	Authenticated bool               `json:"authenticated,omitempty"`
+	Actions       map[string]*Action `json:"actions,omitempty"`
+	Error         *ObjectError       `json:"error,omitempty"`
+}
+
+// Action is a transfer action (download or upload) for one object
+type Action struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header,omitempty"`
+}
+
+// ObjectError reports a per-object error in a batch response
+type ObjectError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Handler serves the LFS batch API and basic transfer adapter.
+// Authentication and repository access checks are done by the server's
+// AuthMiddleware in front of it; see IsWriteRequest.
+type Handler struct {
+	store    *Store
+	reposDir string
+	readOnly bool
+}
+
+// NewHandler creates an LFS handler for repositories in reposDir
+func NewHandler(store *Store, reposDir string, readOnly bool) *Handler {
+	return &Handler{store: store, reposDir: reposDir, readOnly: readOnly}
+}
+
+// IsLFSRequest reports whether a request path belongs to the LFS API
+func IsLFSRequest(r *http.Request) bool {
+	return pathPattern.MatchString(r.URL.Path)
+}
+
+// IsWriteRequest reports whether r uploads LFS objects: an object PUT or a
+// batch request for the upload operation. The batch body is restored so the
+// handler can read it again.
+func IsWriteRequest(r *http.Request) bool {
+	m := pathPattern.FindStringSubmatch(r.URL.Path)
+	if m == nil {
+		return false
+	}
+	if r.Method == http.MethodPut {
+		return true
+	}
+	if r.Method != http.MethodPost || m[2] != "objects/batch" || r.Body == nil {
+		return false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBatchBody))
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		// Treat unreadable requests as writes so they need the higher permission
+		return true
+	}
+
+	var req BatchRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return true
+	}
+	return req.Operation != "download"
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m := pathPattern.FindStringSubmatch(r.URL.Path)
+	if m == nil {
+		http.NotFound(w, r)
+		return
+	}
+	repo, rest := m[1], m[2]
+	if registry.ValidateName(repo) != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	barePath := registry.BarePathFor(h.reposDir, repo)
+	if info, err := os.Stat(barePath); err != nil || !info.IsDir() {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Repository not found: %s.git", repo))
+		return
+	}
+
+	switch {
+	case rest == "objects/batch" && r.Method == http.MethodPost:
+		h.batch(w, r, repo)
+	case strings.HasPrefix(rest, "objects/") && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		h.download(w, r, repo, strings.TrimPrefix(rest, "objects/"))
+	case strings.HasPrefix(rest, "objects/") && r.Method == http.MethodPut:
+		h.upload(w, r, repo, strings.TrimPrefix(rest, "objects/"))
+	default:
+		// Includes the locking API, which LGH does not implement
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// batch answers a batch request with download or upload actions
+func (h *Handler) batch(w http.ResponseWriter, r *http.Request, repo string) {
+	var req BatchRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBatchBody)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid batch request")
+		return
+	}
+
+	switch req.Operation {
+	case "download":
+	case "upload":
+		if h.readOnly {
+			writeError(w, http.StatusForbidden, "Repository is read-only. Uploads are not allowed.")
+			return
+		}
+	default:
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Unsupported operation '%s'", req.Operation))
+		return
+	}
+
+	if len(req.Transfers) > 0 && !contains(req.Transfers, "basic") {
+		writeError(w, http.StatusUnprocessableEntity, "Only the basic transfer adapter is supported")
+		return
+	}
+
+	resp := BatchResponse{Transfer: "basic", HashAlgo: "sha256", Objects: []ObjectResponse{}}
+	for _, obj := range req.Objects {
+		out := ObjectResponse{OID: obj.OID, Size: obj.Size}
+
+		if !ValidOID(obj.OID) || obj.Size < 0 {
+			out.Error = &ObjectError{Code: http.StatusUnprocessableEntity, Message: "Invalid object ID or size"}
+			resp.Objects = append(resp.Objects, out)
+			continue
+		}
+
+		size, exists := h.store.Size(repo, obj.OID)
+		action := &Action{Href: objectURL(r, repo, obj.OID), Header: authHeader(r)}
+
+		if req.Operation == "download" {
+			if !exists {
+				out.Error = &ObjectError{Code: http.StatusNotFound, Message: "Object does not exist"}
+			} else {
+				out.Size = size
+				out.Authenticated = true
+				out.Actions = map[string]*Action{"download": action}
+			}
+		} else if !exists || size != obj.Size {
+			// Objects already stored get no action: the client skips them
+			out.Authenticated = true
+			out.Actions = map[string]*Action{"upload": action}
+		}
+
+		resp.Objects = append(resp.Objects, out)
+	}
+
+	w.Header().Set("Content-Type", MediaType)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// download streams a stored object
+func (h *Handler) download(w http.ResponseWriter, r *http.Request, repo, oid string) {
+	f, err := h.store.Open(repo, oid)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Object does not exist")
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to read object")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, r, "", info.ModTime(), f)
+}
+
+// upload stores an object sent by the basic transfer adapter
+func (h *Handler) upload(w http.ResponseWriter, r *http.Request, repo, oid string) {
+	if h.readOnly {
+		writeError(w, http.StatusForbidden, "Repository is read-only. Uploads are not allowed.")
+		return
+	}
+	if !ValidOID(oid) {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid object ID")
+		return
+	}
+
+	err := h.store.Put(repo, oid, r.ContentLength, r.Body)
+	switch {
+	case errors.Is(err, ErrSizeMismatch), errors.Is(err, ErrHashMismatch):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// objectURL returns the absolute URL of an object for transfer actions
+func objectURL(r *http.Request, repo, oid string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/%s.git/info/lfs/objects/%s", scheme, r.Host, repo, oid)
+}
+
+// authHeader forwards the request credentials to transfer actions, so
+// tokens and Basic credentials work without a second prompt
+func authHeader(r *http.Request) map[string]string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		return map[string]string{"Authorization": auth}
+	}
+	return nil
+}
+
+// writeError sends an LFS JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", MediaType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package lfs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	dir := t.TempDir()
+	reposDir := filepath.Join(dir, "repos")
+	if err := os.MkdirAll(filepath.Join(reposDir, "app.git"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	h := NewHandler(NewStore(filepath.Join(dir, "lfs")), reposDir, false)
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func batch(t *testing.T, srv *httptest.Server, op string, obj Object) ObjectResponse {
+	t.Helper()
+	body, _ := json.Marshal(BatchRequest{Operation: op, Transfers: []string{"basic"}, Objects: []Object{obj}})
+	resp, err := http.Post(srv.URL+"/app.git/info/lfs/objects/batch", MediaType, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("batch %s: status %d", op, resp.StatusCode)
+	}
+	var out BatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Transfer != "basic" || len(out.Objects) != 1 {
+		t.Fatalf("unexpected batch response: %+v", out)
+	}
+	return out.Objects[0]
+}
+
+func TestUploadAndDownload(t *testing.T) {
+	srv := newTestServer(t)
+	content := []byte("large binary content")
+	sum := sha256.Sum256(content)
+	obj := Object{OID: hex.EncodeToString(sum[:]), Size: int64(len(content))}
+
+	if got := batch(t, srv, "download", obj); got.Error == nil || got.Error.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing object, got %+v", got)
+	}
+
+	up := batch(t, srv, "upload", obj)
+	action := up.Actions["upload"]
+	if action == nil {
+		t.Fatalf("expected upload action, got %+v", up)
+	}
+
+	// Wrong content is rejected
+	req, _ := http.NewRequest(http.MethodPut, action.Href, strings.NewReader("tampered content!!!!"))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("tampered upload: status %d", resp.StatusCode)
+	}
+
+	req, _ = http.NewRequest(http.MethodPut, action.Href, bytes.NewReader(content))
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("upload: status %d", resp.StatusCode)
+	}
+
+	// Stored objects need no upload
+	if again := batch(t, srv, "upload", obj); len(again.Actions) != 0 {
+		t.Errorf("expected no actions for stored object, got %+v", again.Actions)
+	}
+
+	down := batch(t, srv, "download", obj)
+	if down.Actions["download"] == nil {
+		t.Fatalf("expected download action, got %+v", down)
+	}
+	resp, err = http.Get(down.Actions["download"].Href)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !bytes.Equal(got, content) {
+		t.Errorf("downloaded %q, want %q", got, content)
+	}
+}
+
+func TestIsWriteRequest(t *testing.T) {
+	tests := []struct {
+		method, path, body string
+		want               bool
+	}{
+		{http.MethodPost, "/app.git/info/lfs/objects/batch", `{"operation":"download"}`, false},
+		{http.MethodPost, "/app.git/info/lfs/objects/batch", `{"operation":"upload"}`, true},
+		{http.MethodPost, "/app.git/info/lfs/objects/batch", `not json`, true},
+		{http.MethodPut, "/app.git/info/lfs/objects/abc", "", true},
+		{http.MethodGet, "/app.git/info/lfs/objects/abc", "", false},
+		{http.MethodPost, "/app.git/git-receive-pack", "", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		if got := IsWriteRequest(r); got != tt.want {
+			t.Errorf("%s %s %q: got %v, want %v", tt.method, tt.path, tt.body, got, tt.want)
+		}
+		// The body must still be readable by the handler
+		if rest, _ := io.ReadAll(r.Body); string(rest) != tt.body {
+			t.Errorf("%s %s: body not restored: %q", tt.method, tt.path, rest)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package lfs implements a Git LFS server (batch API and basic transfer)
+// storing objects under the LGH data directory
+package lfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// oidPattern matches a SHA-256 object ID
+var oidPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// ErrSizeMismatch is returned by Store.Put when the content does not have the announced size
+var ErrSizeMismatch = errors.New("object size does not match")
+
+// ErrHashMismatch is returned by Store.Put when the content does not hash to the object ID
+var ErrHashMismatch = errors.New("object content does not match its ID")
+
+// Store keeps LFS objects per repository:
+// <dir>/<repo>/objects/<oid[0:2]>/<oid[2:4]>/<oid>
+type Store struct {
+	dir string
+}
+
+// NewStore creates a Store rooted at dir
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// GetLFSDir returns the LFS storage directory inside the data directory
+func GetLFSDir(dataDir string) string {
+	return filepath.Join(dataDir, "lfs")
+}
+
+// ValidOID reports whether oid is a well-formed SHA-256 object ID
+func ValidOID(oid string) bool {
+	return oidPattern.MatchString(oid)
+}
+
+// path returns the object path; oid must be valid
+func (s *Store) path(repo, oid string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(repo), "objects", oid[0:2], oid[2:4], oid)
+}
+
+// Size returns the size of a stored object, or false if it does not exist
+func (s *Store) Size(repo, oid string) (int64, bool) {
+	if !ValidOID(oid) {
+		return 0, false
+	}
+	info, err := os.Stat(s.path(repo, oid))
+	if err != nil || !info.Mode().IsRegular() {
+		return 0, false
+	}
+	return info.Size(), true
+}
+
+// Open opens a stored object for reading
+func (s *Store) Open(repo, oid string) (*os.File, error) {
+	if !ValidOID(oid) {
+		return nil, fmt.Errorf("invalid object ID")
+	}
+	// nolint:gosec // G304: path is built from a validated object ID
+	return os.Open(s.path(repo, oid))
+}
+
+// Put stores an object read from r. The content is written to a temporary
+// file and only moved into place once its SHA-256 and size are verified.
+// A negative size skips the size check.
+func (s *Store) Put(repo, oid string, size int64, r io.Reader) error {
+	if !ValidOID(oid) {
+		return fmt.Errorf("invalid object ID")
+	}
+
+	dest := s.path(repo, oid)
+	if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+		return fmt.Errorf("failed to create object directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), oid+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	if size >= 0 && written != size {
+		return ErrSizeMismatch
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != oid {
+		return ErrHashMismatch
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return fmt.Errorf("failed to store object: %w", err)
+	}
+	return nil
+}
+
+// Rename moves the objects of a renamed repository to its new name
+func (s *Store) Rename(oldRepo, newRepo string) error {
+	oldDir := filepath.Join(s.dir, filepath.FromSlash(oldRepo))
+	newDir := filepath.Join(s.dir, filepath.FromSlash(newRepo))
+	// Move objects/ only: the directory may also hold nested namespaces
+	if _, err := os.Stat(filepath.Join(oldDir, "objects")); os.IsNotExist(err) {
+		return nil
+	}
+	if err := os.MkdirAll(newDir, 0700); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(oldDir, "objects"), filepath.Join(newDir, "objects")); err != nil {
+		return err
+	}
+	_ = os.Remove(oldDir)
+	return nil
+}
+
+// Delete removes the objects of a purged repository
+func (s *Store) Delete(repo string) error {
+	dir := filepath.Join(s.dir, filepath.FromSlash(repo))
+	// Delete objects/ only: the directory may also hold nested namespaces
+	if err := os.RemoveAll(filepath.Join(dir, "objects")); err != nil {
+		return err
+	}
+	_ = os.Remove(dir)
+	return nil
+}
@@ -76,6 +76,9 @@ func registerTools(s *server.MCPServer) {
 			mcp.WithString("name",
 				mcp.Description("Optional custom name for the repository on LGH"),
 			),
+			mcp.WithString("namespace",
+				mcp.Description("Optional namespace such as 'team', giving clone URLs like /team/<name>.git"),
+			),
 		),
 		handleAdd,
 	)
@@ -107,9 +107,10 @@ func handleList(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult,
 	for _, repo := range repos {
 		repoList = append(repoList, map[string]interface{}{
 			"name":        repo.Name,
+			"namespace":   repo.Namespace(),
 			"source_path": repo.SourcePath,
 			"bare_path":   repo.BarePath,
-			"clone_url":   fmt.Sprintf("http://%s:%d/lgh/%s.git", cfg.BindAddress, cfg.Port, repo.Name),
+			"clone_url":   cfg.BaseURL(cfg.BindAddress) + registry.URLPath(repo.Name),
 			"created_at":  repo.CreatedAt.Format("2006-01-02 15:04:05"),
 		})
 	}
@@ -122,6 +123,7 @@ func handleAdd(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolR
 	params := getArgsMap(request)
 	path := getString(params, "path")
 	name := getString(params, "name")
+	namespace := getString(params, "namespace")
 
 	if path == "" {
 		return mcp.NewToolResultError("path is required"), nil
@@ -143,6 +145,9 @@ func handleAdd(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolR
 	if name != "" {
 		cmdArgs = append(cmdArgs, "--name", name)
 	}
+	if namespace != "" {
+		cmdArgs = append(cmdArgs, "--namespace", namespace)
+	}
 
 	cmd, err := getLGHCmd(ctx, cmdArgs...)
 	if err != nil {
@@ -585,7 +590,7 @@ func handleResourceServerStatus(_ context.Context, request mcp.ReadResourceReque
 	data, _ := json.MarshalIndent(map[string]interface{}{
 		"running": running,
 		"pid":     pid,
-		"address": fmt.Sprintf("http://%s:%d", cfg.BindAddress, cfg.Port),
+		"address": cfg.BaseURL(cfg.BindAddress),
 	}, "", "  ")
 
 	return []mcp.ResourceContents{
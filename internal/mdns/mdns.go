@@ -37,26 +37,35 @@ const (
 	ServiceDomain = "local."
 )
 
+// tlsInfo is the TXT record advertising that the service speaks HTTPS
+const tlsInfo = "tls=1"
+
 // Service represents an mDNS service for LGH
 type Service struct {
 	server   *mdns.Server
 	port     int
 	hostname string
+	useTLS   bool
 }
 
-// NewService creates a new mDNS service
-func NewService(port int) (*Service, error) {
+// Hostname returns the name advertised over mDNS (without ".local")
+func Hostname() string {
 	hostname, err := os.Hostname()
 	if err != nil {
 		hostname = "lgh-server"
 	}
 
 	// Clean hostname for mDNS
-	hostname = strings.ReplaceAll(hostname, ".", "-")
+	return strings.ReplaceAll(hostname, ".", "-")
+}
 
+// NewService creates a new mDNS service
+// useTLS advertises https:// URLs to clients
+func NewService(port int, useTLS bool) (*Service, error) {
 	return &Service{
 		port:     port,
-		hostname: hostname,
+		hostname: Hostname(),
+		useTLS:   useTLS,
 	}, nil
 }
 
@@ -73,6 +82,9 @@ func (s *Service) Start() error {
 		"LGH LocalGitHub Service",
 		"version=1.0.0",
 	}
+	if s.useTLS {
+		info = append(info, tlsInfo)
+	}
 
 	service, err := mdns.NewMDNSService(
 		s.hostname,
@@ -107,7 +119,11 @@ func (s *Service) Stop() error {
 
 // GetServiceURL returns the mDNS URL for the service
 func (s *Service) GetServiceURL() string {
-	return fmt.Sprintf("http://%s.local:%d", s.hostname, s.port)
+	scheme := "http"
+	if s.useTLS {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s.local:%d", scheme, s.hostname, s.port)
 }
 
 // DiscoveryResult represents a discovered LGH service
@@ -142,10 +158,17 @@ func Discover(_ int) ([]DiscoveryResult, error) {
 			Info: entry.InfoFields,
 		}
 
+		scheme := "http"
+		for _, field := range entry.InfoFields {
+			if field == tlsInfo {
+				scheme = "https"
+			}
+		}
+
 		if entry.AddrV4 != nil {
-			result.URL = fmt.Sprintf("http://%s:%d", entry.AddrV4.String(), entry.Port)
+			result.URL = fmt.Sprintf("%s://%s:%d", scheme, entry.AddrV4.String(), entry.Port)
 		} else if entry.Host != "" {
-			result.URL = fmt.Sprintf("http://%s:%d", entry.Host, entry.Port)
+			result.URL = fmt.Sprintf("%s://%s:%d", scheme, entry.Host, entry.Port)
 		}
 
 		results = append(results, result)
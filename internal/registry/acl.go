@@ -0,0 +1,176 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Permission is an access level on a single repository
+type Permission string
+
+const (
+	// PermRead allows clone and fetch
+	PermRead Permission = "read"
+	// PermWrite allows push in addition to read
+	PermWrite Permission = "write"
+	// PermAdmin allows API changes (e.g. commit statuses) in addition to write
+	PermAdmin Permission = "admin"
+)
+
+// level orders permissions so that a higher permission includes the lower ones
+func (p Permission) level() int {
+	switch p {
+	case PermRead:
+		return 1
+	case PermWrite:
+		return 2
+	case PermAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Includes reports whether p grants at least the required permission
+func (p Permission) Includes(required Permission) bool {
+	return p.level() > 0 && p.level() >= required.level()
+}
+
+// ParsePermission validates a permission name
+func ParsePermission(name string) (Permission, error) {
+	p := Permission(strings.ToLower(name))
+	if p.level() == 0 {
+		return "", fmt.Errorf("invalid permission '%s': must be one of read, write, admin", name)
+	}
+	return p, nil
+}
+
+// ACLEntry grants a permission on a repository to a user or a group
+type ACLEntry struct {
+	User       string     `yaml:"user,omitempty"`
+	Group      string     `yaml:"group,omitempty"`
+	Permission Permission `yaml:"permission"`
+}
+
+// Subject returns a display name for the entry, e.g. "alice" or "group:devs"
+func (e ACLEntry) Subject() string {
+	if e.Group != "" {
+		return "group:" + e.Group
+	}
+	return e.User
+}
+
+// sameSubject reports whether two entries refer to the same user or group
+func (e ACLEntry) sameSubject(o ACLEntry) bool {
+	return e.User == o.User && e.Group == o.Group
+}
+
+// Restricted reports whether the repository has an access list.
+// Repositories without one are open to every authenticated user.
+func (m *RepoMapping) Restricted() bool {
+	return len(m.ACL) > 0
+}
+
+// PermissionFor returns the highest permission granted to the user directly
+// or through one of its groups, or "" if none is granted.
+func (m *RepoMapping) PermissionFor(user string, groups []string) Permission {
+	var best Permission
+	for _, e := range m.ACL {
+		match := e.User != "" && e.User == user
+		if !match && e.Group != "" {
+			for _, g := range groups {
+				if g == e.Group {
+					match = true
+					break
+				}
+			}
+		}
+		if match && e.Permission.level() > best.level() {
+			best = e.Permission
+		}
+	}
+	return best
+}
+
+// Grant adds or replaces an access list entry on a repository
+func (r *Registry) Grant(name string, entry ACLEntry) error {
+	if (entry.User == "") == (entry.Group == "") {
+		return fmt.Errorf("exactly one of user or group is required")
+	}
+	if entry.Permission.level() == 0 {
+		return fmt.Errorf("invalid permission '%s'", entry.Permission)
+	}
+
+	mappings, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	for i := range mappings.Repos {
+		repo := &mappings.Repos[i]
+		if repo.Name != name {
+			continue
+		}
+
+		for j := range repo.ACL {
+			if repo.ACL[j].sameSubject(entry) {
+				repo.ACL[j].Permission = entry.Permission
+				return r.save(mappings)
+			}
+		}
+		repo.ACL = append(repo.ACL, entry)
+		return r.save(mappings)
+	}
+
+	return fmt.Errorf("repository '%s' not found", name)
+}
+
+// Revoke removes the access list entry for a user or group from a repository
+func (r *Registry) Revoke(name string, entry ACLEntry) error {
+	mappings, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	for i := range mappings.Repos {
+		repo := &mappings.Repos[i]
+		if repo.Name != name {
+			continue
+		}
+
+		newACL := []ACLEntry{}
+		for _, e := range repo.ACL {
+			if !e.sameSubject(entry) {
+				newACL = append(newACL, e)
+			}
+		}
+		if len(newACL) == len(repo.ACL) {
+			return fmt.Errorf("'%s' has no access entry on '%s'", entry.Subject(), name)
+		}
+
+		repo.ACL = newACL
+		return r.save(mappings)
+	}
+
+	return fmt.Errorf("repository '%s' not found", name)
+}
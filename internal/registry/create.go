@@ -0,0 +1,160 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// AnyNamespace in a create rule matches every namespace and the top level
+	AnyNamespace = "*"
+	// AnyUser in a create rule matches every authenticated user
+	AnyUser = "*"
+)
+
+// CreateRule lets a user or group create repositories in a namespace by
+// pushing to a URL that does not exist yet (push-to-create)
+type CreateRule struct {
+	// Namespace is "" for top-level repositories or "*" for any namespace
+	Namespace string `yaml:"namespace"`
+	User      string `yaml:"user,omitempty"`
+	Group     string `yaml:"group,omitempty"`
+}
+
+// Subject returns a display name for the rule, e.g. "alice", "group:devs" or "*"
+func (c CreateRule) Subject() string {
+	return ACLEntry{User: c.User, Group: c.Group}.Subject()
+}
+
+// NamespaceLabel returns a display name for the rule's namespace
+func (c CreateRule) NamespaceLabel() string {
+	switch c.Namespace {
+	case "":
+		return "(top level)"
+	case AnyNamespace:
+		return "* (any)"
+	}
+	return c.Namespace + "/"
+}
+
+// matches reports whether the rule allows user to create in namespace.
+// A namespace rule also covers its nested namespaces.
+func (c CreateRule) matches(namespace, user string, groups []string) bool {
+	if c.Namespace != AnyNamespace && c.Namespace != namespace &&
+		(c.Namespace == "" || !strings.HasPrefix(namespace, c.Namespace+"/")) {
+		return false
+	}
+	if c.User == AnyUser || (c.User != "" && c.User == user) {
+		return true
+	}
+	for _, g := range groups {
+		if c.Group != "" && g == c.Group {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the namespace and subject of a rule
+func (c CreateRule) validate() error {
+	if (c.User == "") == (c.Group == "") {
+		return fmt.Errorf("exactly one of user or group is required")
+	}
+	if c.Namespace != "" && c.Namespace != AnyNamespace {
+		if err := ValidateName(c.Namespace + "/x"); err != nil {
+			return fmt.Errorf("invalid namespace '%s': %w", c.Namespace, err)
+		}
+	}
+	return nil
+}
+
+// CreateRules returns the push-to-create rules
+func (r *Registry) CreateRules() ([]CreateRule, error) {
+	mappings, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	return mappings.CreateRules, nil
+}
+
+// AllowCreate adds a push-to-create rule
+func (r *Registry) AllowCreate(rule CreateRule) error {
+	if err := rule.validate(); err != nil {
+		return err
+	}
+
+	mappings, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	for _, c := range mappings.CreateRules {
+		if c == rule {
+			return nil
+		}
+	}
+	mappings.CreateRules = append(mappings.CreateRules, rule)
+	return r.save(mappings)
+}
+
+// DenyCreate removes a push-to-create rule
+func (r *Registry) DenyCreate(rule CreateRule) error {
+	mappings, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	newRules := []CreateRule{}
+	for _, c := range mappings.CreateRules {
+		if c != rule {
+			newRules = append(newRules, c)
+		}
+	}
+	if len(newRules) == len(mappings.CreateRules) {
+		return fmt.Errorf("'%s' cannot create repositories in %s", rule.Subject(), rule.NamespaceLabel())
+	}
+
+	mappings.CreateRules = newRules
+	return r.save(mappings)
+}
+
+// CanCreate reports whether a push by user (member of groups) may create the
+// repository name. Without any rule push-to-create is disabled.
+func (r *Registry) CanCreate(name, user string, groups []string) (bool, error) {
+	if err := ValidateName(name); err != nil {
+		return false, err
+	}
+
+	rules, err := r.CreateRules()
+	if err != nil {
+		return false, err
+	}
+
+	namespace, _ := SplitName(name)
+	for _, c := range rules {
+		if c.matches(namespace, user, groups) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCanCreate(t *testing.T) {
+	r := NewWithPath(filepath.Join(t.TempDir(), "mappings.yaml"))
+
+	if ok, _ := r.CanCreate("team/app", "alice", nil); ok {
+		t.Fatal("push-to-create should be disabled without rules")
+	}
+
+	rules := []CreateRule{
+		{Namespace: "team", Group: "devs"},
+		{Namespace: "", User: "bob"},
+		{Namespace: AnyNamespace, User: "root"},
+	}
+	for _, rule := range rules {
+		if err := r.AllowCreate(rule); err != nil {
+			t.Fatalf("AllowCreate(%+v) failed: %v", rule, err)
+		}
+	}
+
+	tests := []struct {
+		name, user string
+		groups     []string
+		want       bool
+	}{
+		{"team/app", "alice", []string{"devs"}, true},
+		{"team/web/app", "alice", []string{"devs"}, true},
+		{"teamx/app", "alice", []string{"devs"}, false},
+		{"app", "alice", []string{"devs"}, false},
+		{"app", "bob", nil, true},
+		{"team/app", "bob", nil, false},
+		{"any/where/app", "root", nil, true},
+		{"../app", "root", nil, false},
+	}
+	for _, tt := range tests {
+		if got, _ := r.CanCreate(tt.name, tt.user, tt.groups); got != tt.want {
+			t.Errorf("CanCreate(%q, %q) = %v, want %v", tt.name, tt.user, got, tt.want)
+		}
+	}
+
+	if err := r.DenyCreate(CreateRule{Namespace: "", User: "bob"}); err != nil {
+		t.Fatalf("DenyCreate() failed: %v", err)
+	}
+	if ok, _ := r.CanCreate("app", "bob", nil); ok {
+		t.Error("bob should not create after DenyCreate")
+	}
+	if err := r.DenyCreate(CreateRule{Namespace: "", User: "bob"}); err == nil {
+		t.Error("DenyCreate() should fail for a missing rule")
+	}
+}
+
+func TestCreateRuleValidate(t *testing.T) {
+	r := NewWithPath(filepath.Join(t.TempDir(), "mappings.yaml"))
+	invalid := []CreateRule{
+		{Namespace: "team"},
+		{Namespace: "team", User: "a", Group: "b"},
+		{Namespace: "lgh", User: "a"},
+		{Namespace: "../x", User: "a"},
+	}
+	for _, rule := range invalid {
+		if err := r.AllowCreate(rule); err == nil {
+			t.Errorf("AllowCreate(%+v) should fail", rule)
+		}
+	}
+}
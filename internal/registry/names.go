@@ -0,0 +1,167 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LegacyNamespace is the virtual owner in remote URLs of repositories
+// without a namespace (http://host/lgh/app.git)
+const LegacyNamespace = "lgh"
+
+// reservedNamespaces cannot be used as the first namespace segment because
+// they collide with legacy URLs or server routes
+var reservedNamespaces = map[string]bool{
+	LegacyNamespace: true,
+	"api":           true,
+	"debug":         true,
+	"health":        true,
+}
+
+// ValidateName checks a repository name such as "app" or "team/project".
+// Every segment must be a plain directory name and only the last one may
+// end in .git.
+func ValidateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("repository name is empty")
+	}
+	if strings.ContainsAny(name, "\\'\"\x00") {
+		return fmt.Errorf("invalid repository name '%s'", name)
+	}
+
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		if part == "" || part == "." || part == ".." || strings.HasPrefix(part, ".") || strings.HasPrefix(part, "-") {
+			return fmt.Errorf("invalid repository name '%s'", name)
+		}
+		if i < len(parts)-1 && strings.HasSuffix(part, ".git") {
+			return fmt.Errorf("invalid repository name '%s': namespaces cannot end in .git", name)
+		}
+	}
+	if len(parts) > 1 && reservedNamespaces[parts[0]] {
+		return fmt.Errorf("namespace '%s' is reserved", parts[0])
+	}
+	return nil
+}
+
+// JoinName builds a repository name from a namespace and a short name
+func JoinName(namespace, base string) string {
+	namespace = strings.Trim(namespace, "/")
+	if namespace == "" {
+		return base
+	}
+	return namespace + "/" + base
+}
+
+// SplitName splits "team/sub/app" into "team/sub" and "app"
+func SplitName(name string) (namespace, base string) {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// Namespace returns the namespace of the repository ("" for top-level repos)
+func (m RepoMapping) Namespace() string {
+	namespace, _ := SplitName(m.Name)
+	return namespace
+}
+
+// ShortName returns the repository name without its namespace
+func (m RepoMapping) ShortName() string {
+	_, base := SplitName(m.Name)
+	return base
+}
+
+// BarePathFor returns where the bare repository for name lives in reposDir
+func BarePathFor(reposDir, name string) string {
+	return filepath.Join(reposDir, filepath.FromSlash(name)+".git")
+}
+
+// URLPath returns the path of a repository in remote URLs:
+// "/team/project.git", or "/lgh/app.git" for top-level repositories
+func URLPath(name string) string {
+	if !strings.Contains(name, "/") {
+		name = LegacyNamespace + "/" + name
+	}
+	return "/" + name + ".git"
+}
+
+// NameFromPath extracts the repository name from a request path such as
+// "/team/project.git/info/refs" or "/lgh/app.git". rest is whatever follows
+// the ".git" segment.
+func NameFromPath(p string) (name, rest string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	for i, part := range parts {
+		if !strings.HasSuffix(part, ".git") || part == ".git" {
+			continue
+		}
+		segments := parts[:i+1]
+		if len(segments) > 1 && segments[0] == LegacyNamespace {
+			segments = segments[1:]
+		}
+		name = strings.TrimSuffix(strings.Join(segments, "/"), ".git")
+		if ValidateName(name) != nil {
+			return "", "", false
+		}
+		if i+1 < len(parts) {
+			rest = "/" + strings.Join(parts[i+1:], "/")
+		}
+		return name, rest, true
+	}
+	return "", "", false
+}
+
+// IsPathSafe checks if targetPath is safely within basePath
+// SECURITY: Prevents path traversal attacks
+func IsPathSafe(basePath, targetPath string) bool {
+	// Resolve to absolute paths
+	absBase, err := filepath.Abs(basePath)
+	if err != nil {
+		return false
+	}
+	absTarget, err := filepath.Abs(targetPath)
+	if err != nil {
+		return false
+	}
+
+	// Resolve any symlinks
+	realBase, err := filepath.EvalSymlinks(absBase)
+	if err != nil {
+		realBase = absBase
+	}
+	realTarget, err := filepath.EvalSymlinks(absTarget)
+	if err != nil {
+		// Target might not exist yet, use absolute path
+		realTarget = absTarget
+	}
+
+	// Ensure target is within base (with trailing slash to prevent prefix attacks)
+	if !strings.HasSuffix(realBase, string(os.PathSeparator)) {
+		realBase += string(os.PathSeparator)
+	}
+
+	return strings.HasPrefix(realTarget, realBase) || realTarget == strings.TrimSuffix(realBase, string(os.PathSeparator))
+}
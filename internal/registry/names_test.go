@@ -0,0 +1,89 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateName(t *testing.T) {
+	valid := []string{"app", "my-app.v2", "team/app", "team/sub/app", "lgh"}
+	for _, name := range valid {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) = %v, want nil", name, err)
+		}
+	}
+
+	invalid := []string{"", "/app", "team/", "a//b", "../app", "team/../app", ".hidden", "-x", "team.git/app", "lgh/app", "api/app", `a\b`}
+	for _, name := range invalid {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) = nil, want error", name)
+		}
+	}
+}
+
+func TestSplitName(t *testing.T) {
+	rm := RepoMapping{Name: "team/sub/app"}
+	if rm.Namespace() != "team/sub" || rm.ShortName() != "app" {
+		t.Errorf("got (%q, %q)", rm.Namespace(), rm.ShortName())
+	}
+	rm = RepoMapping{Name: "app"}
+	if rm.Namespace() != "" || rm.ShortName() != "app" {
+		t.Errorf("got (%q, %q)", rm.Namespace(), rm.ShortName())
+	}
+	if got := JoinName("/team/", "app"); got != "team/app" {
+		t.Errorf("JoinName() = %q", got)
+	}
+}
+
+func TestURLPath(t *testing.T) {
+	if got := URLPath("app"); got != "/lgh/app.git" {
+		t.Errorf("URLPath(app) = %q", got)
+	}
+	if got := URLPath("team/app"); got != "/team/app.git" {
+		t.Errorf("URLPath(team/app) = %q", got)
+	}
+	if got := BarePathFor("/repos", "team/app"); got != filepath.Join("/repos", "team", "app.git") {
+		t.Errorf("BarePathFor() = %q", got)
+	}
+}
+
+func TestNameFromPath(t *testing.T) {
+	tests := []struct {
+		path, name, rest string
+		ok               bool
+	}{
+		{"/app.git/info/refs", "app", "/info/refs", true},
+		{"/lgh/app.git/git-upload-pack", "app", "/git-upload-pack", true},
+		{"/team/app.git", "team/app", "", true},
+		{"/team/sub/app.git/info/lfs/objects/batch", "team/sub/app", "/info/lfs/objects/batch", true},
+		{"/team/../app.git/info/refs", "", "", false},
+		{"/app/info/refs", "", "", false},
+		{"/.git/config", "", "", false},
+	}
+	for _, tt := range tests {
+		name, rest, ok := NameFromPath(tt.path)
+		if ok != tt.ok || name != tt.name || rest != tt.rest {
+			t.Errorf("NameFromPath(%q) = (%q, %q, %v), want (%q, %q, %v)", tt.path, name, rest, ok, tt.name, tt.rest, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// BranchProtection restricts how a branch (or branch pattern) can be updated.
+// Rules are enforced by the managed pre-receive hook before refs change.
+type BranchProtection struct {
+	// Branch is a branch name or a glob such as "release/*"
+	Branch         string   `yaml:"branch"`
+	NoForcePush    bool     `yaml:"no_force_push,omitempty"`
+	NoDeletion     bool     `yaml:"no_deletion,omitempty"`
+	LinearHistory  bool     `yaml:"linear_history,omitempty"`
+	AllowedPushers []string `yaml:"allowed_pushers,omitempty"` // users, or "group:<name>"
+}
+
+// Matches reports whether the rule applies to the given branch name
+func (p BranchProtection) Matches(branch string) bool {
+	if p.Branch == branch {
+		return true
+	}
+	ok, err := path.Match(p.Branch, branch)
+	return err == nil && ok
+}
+
+// AllowsPusher reports whether the user (or one of its groups) may push.
+// An empty list allows everyone with write access.
+func (p BranchProtection) AllowsPusher(user string, groups []string) bool {
+	if len(p.AllowedPushers) == 0 {
+		return true
+	}
+	for _, allowed := range p.AllowedPushers {
+		if group, ok := strings.CutPrefix(allowed, "group:"); ok {
+			for _, g := range groups {
+				if g == group {
+					return true
+				}
+			}
+		} else if allowed == user {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary returns a short description of the rule, e.g. "no force-push, no deletion"
+func (p BranchProtection) Summary() string {
+	parts := []string{}
+	if p.NoForcePush {
+		parts = append(parts, "no force-push")
+	}
+	if p.NoDeletion {
+		parts = append(parts, "no deletion")
+	}
+	if p.LinearHistory {
+		parts = append(parts, "linear history")
+	}
+	if len(p.AllowedPushers) > 0 {
+		parts = append(parts, "pushers: "+strings.Join(p.AllowedPushers, ", "))
+	}
+	if len(parts) == 0 {
+		return "no restrictions"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ProtectionFor returns the rules matching a branch. An exact match comes
+// first; every matching rule is enforced.
+func (m *RepoMapping) ProtectionFor(branch string) []BranchProtection {
+	var exact, globs []BranchProtection
+	for _, p := range m.Protections {
+		switch {
+		case p.Branch == branch:
+			exact = append(exact, p)
+		case p.Matches(branch):
+			globs = append(globs, p)
+		}
+	}
+	return append(exact, globs...)
+}
+
+// Protect adds or replaces the protection rule for a branch on a repository
+func (r *Registry) Protect(name string, rule BranchProtection) error {
+	if rule.Branch == "" {
+		return fmt.Errorf("branch is required")
+	}
+	if _, err := path.Match(rule.Branch, ""); err != nil {
+		return fmt.Errorf("invalid branch pattern '%s': %w", rule.Branch, err)
+	}
+
+	mappings, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	for i := range mappings.Repos {
+		repo := &mappings.Repos[i]
+		if repo.Name != name {
+			continue
+		}
+
+		for j := range repo.Protections {
+			if repo.Protections[j].Branch == rule.Branch {
+				repo.Protections[j] = rule
+				return r.save(mappings)
+			}
+		}
+		repo.Protections = append(repo.Protections, rule)
+		return r.save(mappings)
+	}
+
+	return fmt.Errorf("repository '%s' not found", name)
+}
+
+// Unprotect removes the protection rule for a branch from a repository
+func (r *Registry) Unprotect(name, branch string) error {
+	mappings, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	for i := range mappings.Repos {
+		repo := &mappings.Repos[i]
+		if repo.Name != name {
+			continue
+		}
+
+		newRules := []BranchProtection{}
+		for _, p := range repo.Protections {
+			if p.Branch != branch {
+				newRules = append(newRules, p)
+			}
+		}
+		if len(newRules) == len(repo.Protections) {
+			return fmt.Errorf("branch '%s' is not protected on '%s'", branch, name)
+		}
+
+		repo.Protections = newRules
+		return r.save(mappings)
+	}
+
+	return fmt.Errorf("repository '%s' not found", name)
+}
+
+// ScanPolicy controls what the server-side push scan does with findings
+type ScanPolicy string
+
+const (
+	// ScanBlock rejects pushes containing sensitive or oversized files
+	ScanBlock ScanPolicy = "block"
+	// ScanWarn accepts such pushes but shows the findings to the pusher (default)
+	ScanWarn ScanPolicy = "warn"
+	// ScanOff disables the scan
+	ScanOff ScanPolicy = "off"
+)
+
+// ParseScanPolicy validates a scan policy name
+func ParseScanPolicy(name string) (ScanPolicy, error) {
+	switch p := ScanPolicy(strings.ToLower(name)); p {
+	case ScanBlock, ScanWarn, ScanOff:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid scan policy '%s': must be one of block, warn, off", name)
+	}
+}
+
+// EffectiveScanPolicy returns the repository's scan policy, warn if unset.
+// Blocking is opt-in so that existing repositories keep accepting pushes.
+func (m *RepoMapping) EffectiveScanPolicy() ScanPolicy {
+	if m.ScanPolicy == "" {
+		return ScanWarn
+	}
+	return m.ScanPolicy
+}
+
+// SetScanPolicy sets the push scan policy of a repository
+func (r *Registry) SetScanPolicy(name string, policy ScanPolicy) error {
+	if _, err := ParseScanPolicy(string(policy)); err != nil {
+		return err
+	}
+
+	mappings, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	for i := range mappings.Repos {
+		if mappings.Repos[i].Name == name {
+			mappings.Repos[i].ScanPolicy = policy
+			return r.save(mappings)
+		}
+	}
+
+	return fmt.Errorf("repository '%s' not found", name)
+}
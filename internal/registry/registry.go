@@ -22,6 +22,7 @@
 package registry
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -35,17 +36,38 @@ import (
 
 // FileLock is defined in lock_unix.go and lock_windows.go
 
+// ErrNotFound is returned when no repository matches a lookup
+var ErrNotFound = errors.New("not found")
+
 // RepoMapping represents a single repository mapping
 type RepoMapping struct {
+	// Name is unique and may include a namespace ("team/project")
 	Name       string    `yaml:"name"`
 	SourcePath string    `yaml:"source_path"`
 	BarePath   string    `yaml:"bare_path"`
 	CreatedAt  time.Time `yaml:"created_at"`
+	// ACL restricts access to listed users and groups (empty = all authenticated users)
+	ACL []ACLEntry `yaml:"acl,omitempty"`
+	// Protections guard branches against force-push, deletion, ...
+	Protections []BranchProtection `yaml:"protected_branches,omitempty"`
+	// ScanPolicy decides whether pushes with sensitive or large files are rejected
+	ScanPolicy ScanPolicy `yaml:"scan_policy,omitempty"`
+	// CreatedBy is the user whose push or API call created the repository;
+	// such repositories have no SourcePath
+	CreatedBy string `yaml:"created_by,omitempty"`
+	// Description is a short free-form summary of the repository
+	Description string `yaml:"description,omitempty"`
 }
 
 // Mappings holds all repository mappings
 type Mappings struct {
 	Repos []RepoMapping `yaml:"repos"`
+	// CreateRules allow pushes to create new repositories (none = disabled)
+	CreateRules []CreateRule `yaml:"push_to_create,omitempty"`
+	// Redirects map old names of renamed repositories to their new names
+	Redirects []Redirect `yaml:"redirects,omitempty"`
+	// Trash holds removed repositories that can still be restored
+	Trash []TrashedRepo `yaml:"trash,omitempty"`
 }
 
 // Registry manages the mappings file
@@ -127,6 +149,20 @@ func (r *Registry) save(mappings *Mappings) error {
 
 // Add adds a new repository mapping
 func (r *Registry) Add(name, sourcePath, barePath string) error {
+	return r.AddMapping(RepoMapping{
+		Name:       name,
+		SourcePath: sourcePath,
+		BarePath:   barePath,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// AddMapping adds a fully populated repository mapping
+func (r *Registry) AddMapping(m RepoMapping) error {
+	if err := ValidateName(m.Name); err != nil {
+		return err
+	}
+
 	mappings, err := r.load()
 	if err != nil {
 		return err
@@ -134,17 +170,21 @@ func (r *Registry) Add(name, sourcePath, barePath string) error {
 
 	// Check if already exists
 	for _, repo := range mappings.Repos {
-		if repo.Name == name {
-			return fmt.Errorf("repository '%s' already exists", name)
+		if repo.Name == m.Name {
+			return fmt.Errorf("repository '%s' already exists", m.Name)
 		}
 	}
 
-	mappings.Repos = append(mappings.Repos, RepoMapping{
-		Name:       name,
-		SourcePath: sourcePath,
-		BarePath:   barePath,
-		CreatedAt:  time.Now(),
-	})
+	mappings.Repos = append(mappings.Repos, m)
+
+	// A new repository takes precedence over a redirect from its name
+	redirects := []Redirect{}
+	for _, d := range mappings.Redirects {
+		if d.From != m.Name {
+			redirects = append(redirects, d)
+		}
+	}
+	mappings.Redirects = redirects
 
 	return r.save(mappings)
 }
@@ -174,6 +214,23 @@ func (r *Registry) Remove(name string) error {
 	return r.save(mappings)
 }
 
+// SetDescription sets the description of a repository
+func (r *Registry) SetDescription(name, description string) error {
+	mappings, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	for i := range mappings.Repos {
+		if mappings.Repos[i].Name == name {
+			mappings.Repos[i].Description = description
+			return r.save(mappings)
+		}
+	}
+
+	return fmt.Errorf("repository '%s' not found", name)
+}
+
 // List returns all repository mappings
 func (r *Registry) List() ([]RepoMapping, error) {
 	mappings, err := r.load()
@@ -196,7 +253,7 @@ func (r *Registry) Find(name string) (*RepoMapping, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("repository '%s' not found", name)
+	return nil, fmt.Errorf("repository '%s' %w", name, ErrNotFound)
 }
 
 // FindBySourcePath finds a repository mapping by source path
@@ -217,7 +274,28 @@ func (r *Registry) FindBySourcePath(sourcePath string) (*RepoMapping, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("repository at path '%s' not found", sourcePath)
+	return nil, fmt.Errorf("repository at path '%s' %w", sourcePath, ErrNotFound)
+}
+
+// FindByBarePath finds a repository mapping by its bare repository path
+func (r *Registry) FindByBarePath(barePath string) (*RepoMapping, error) {
+	mappings, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	absPath, err := filepath.Abs(barePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, repo := range mappings.Repos {
+		if filepath.Clean(repo.BarePath) == absPath {
+			return &repo, nil
+		}
+	}
+
+	return nil, fmt.Errorf("repository at path '%s' %w", barePath, ErrNotFound)
 }
 
 // Exists checks if a repository with the given name exists
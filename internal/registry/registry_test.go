@@ -156,6 +156,23 @@ func TestRegistryRemoveNotFound(t *testing.T) {
 	}
 }
 
+func TestRegistrySetDescription(t *testing.T) {
+	tmpDir := t.TempDir()
+	r := NewWithPath(filepath.Join(tmpDir, "mappings.yaml"))
+
+	r.Add("repo1", "/source1", "/bare1")
+	if err := r.SetDescription("repo1", "Demo service"); err != nil {
+		t.Fatalf("SetDescription() failed: %v", err)
+	}
+	repo, _ := r.Find("repo1")
+	if repo.Description != "Demo service" {
+		t.Errorf("Description = %q, want %q", repo.Description, "Demo service")
+	}
+	if err := r.SetDescription("nonexistent", "x"); err == nil {
+		t.Error("SetDescription() should fail for nonexistent repo")
+	}
+}
+
 // ---- Registry Find ----
 
 func TestRegistryFind(t *testing.T) {
@@ -296,3 +313,141 @@ func TestMappingsYAML(t *testing.T) {
 		t.Errorf("len(m.Repos) = %d, want 2", len(m.Repos))
 	}
 }
+
+// ---- ACL ----
+
+func TestParsePermission(t *testing.T) {
+	if p, err := ParsePermission("Write"); err != nil || p != PermWrite {
+		t.Errorf("ParsePermission(Write) = %q, %v", p, err)
+	}
+	if _, err := ParsePermission("owner"); err == nil {
+		t.Error("ParsePermission(owner) should fail")
+	}
+}
+
+func TestPermissionIncludes(t *testing.T) {
+	if !PermAdmin.Includes(PermWrite) || !PermWrite.Includes(PermRead) {
+		t.Error("higher permissions should include lower ones")
+	}
+	if PermRead.Includes(PermWrite) {
+		t.Error("read should not include write")
+	}
+	if Permission("").Includes(PermRead) {
+		t.Error("empty permission should include nothing")
+	}
+}
+
+func TestGrantRevoke(t *testing.T) {
+	r := NewWithPath(filepath.Join(t.TempDir(), "mappings.yaml"))
+	_ = r.Add("client", "/src", "/bare")
+
+	if err := r.Grant("client", ACLEntry{User: "alice", Permission: PermRead}); err != nil {
+		t.Fatalf("Grant() error: %v", err)
+	}
+	// Re-granting replaces the existing entry
+	if err := r.Grant("client", ACLEntry{User: "alice", Permission: PermWrite}); err != nil {
+		t.Fatalf("Grant() error: %v", err)
+	}
+	if err := r.Grant("client", ACLEntry{Group: "devs", Permission: PermRead}); err != nil {
+		t.Fatalf("Grant() error: %v", err)
+	}
+
+	repo, _ := r.Find("client")
+	if len(repo.ACL) != 2 {
+		t.Fatalf("ACL has %d entries, want 2", len(repo.ACL))
+	}
+	if got := repo.PermissionFor("alice", nil); got != PermWrite {
+		t.Errorf("PermissionFor(alice) = %q, want %q", got, PermWrite)
+	}
+	if got := repo.PermissionFor("bob", []string{"devs"}); got != PermRead {
+		t.Errorf("PermissionFor(bob, devs) = %q, want %q", got, PermRead)
+	}
+	if got := repo.PermissionFor("eve", nil); got != "" {
+		t.Errorf("PermissionFor(eve) = %q, want none", got)
+	}
+
+	if err := r.Revoke("client", ACLEntry{User: "alice"}); err != nil {
+		t.Fatalf("Revoke() error: %v", err)
+	}
+	if err := r.Revoke("client", ACLEntry{User: "alice"}); err == nil {
+		t.Error("Revoke() should fail for missing entry")
+	}
+	if err := r.Grant("missing", ACLEntry{User: "alice", Permission: PermRead}); err == nil {
+		t.Error("Grant() should fail for unknown repository")
+	}
+	if err := r.Grant("client", ACLEntry{Permission: PermRead}); err == nil {
+		t.Error("Grant() should fail without user or group")
+	}
+}
+
+func TestRestricted(t *testing.T) {
+	open := RepoMapping{Name: "open"}
+	if open.Restricted() {
+		t.Error("repo without ACL should not be restricted")
+	}
+	closed := RepoMapping{Name: "closed", ACL: []ACLEntry{{User: "alice", Permission: PermRead}}}
+	if !closed.Restricted() {
+		t.Error("repo with ACL should be restricted")
+	}
+}
+
+// ---- Branch protection ----
+
+func TestProtectAndUnprotect(t *testing.T) {
+	r := NewWithPath(filepath.Join(t.TempDir(), "mappings.yaml"))
+	if err := r.Add("app", "/src/app", "/repos/app.git"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Protect("app", BranchProtection{Branch: "main", NoForcePush: true}); err != nil {
+		t.Fatalf("Protect() error: %v", err)
+	}
+	if err := r.Protect("app", BranchProtection{Branch: "main", NoDeletion: true}); err != nil {
+		t.Fatalf("Protect() error: %v", err)
+	}
+	if err := r.Protect("app", BranchProtection{Branch: "release/*", LinearHistory: true}); err != nil {
+		t.Fatalf("Protect() error: %v", err)
+	}
+
+	repo, _ := r.Find("app")
+	if len(repo.Protections) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(repo.Protections))
+	}
+	if rules := repo.ProtectionFor("main"); len(rules) != 1 || rules[0].NoForcePush || !rules[0].NoDeletion {
+		t.Errorf("ProtectionFor(main) = %+v, want the replaced rule", rules)
+	}
+	if rules := repo.ProtectionFor("release/1.0"); len(rules) != 1 || !rules[0].LinearHistory {
+		t.Errorf("ProtectionFor(release/1.0) = %+v, want the glob rule", rules)
+	}
+	if rules := repo.ProtectionFor("feature/x"); len(rules) != 0 {
+		t.Errorf("ProtectionFor(feature/x) = %+v, want none", rules)
+	}
+
+	if err := r.Unprotect("app", "main"); err != nil {
+		t.Fatalf("Unprotect() error: %v", err)
+	}
+	if err := r.Unprotect("app", "main"); err == nil {
+		t.Error("Unprotect() should fail for unprotected branch")
+	}
+	if err := r.Protect("app", BranchProtection{Branch: "[bad"}); err == nil {
+		t.Error("Protect() should reject an invalid pattern")
+	}
+}
+
+func TestAllowsPusher(t *testing.T) {
+	open := BranchProtection{Branch: "main"}
+	if !open.AllowsPusher("anyone", nil) {
+		t.Error("empty pusher list should allow everyone")
+	}
+
+	rule := BranchProtection{Branch: "main", AllowedPushers: []string{"alice", "group:leads"}}
+	if !rule.AllowsPusher("alice", nil) {
+		t.Error("alice should be allowed")
+	}
+	if !rule.AllowsPusher("bob", []string{"devs", "leads"}) {
+		t.Error("members of leads should be allowed")
+	}
+	if rule.AllowsPusher("carol", []string{"devs"}) {
+		t.Error("carol should not be allowed")
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"fmt"
+	"time"
+)
+
+// Redirect keeps the old name of a renamed repository answering until ExpiresAt
+type Redirect struct {
+	From      string    `yaml:"from"`
+	To        string    `yaml:"to"`
+	ExpiresAt time.Time `yaml:"expires_at"`
+}
+
+// Expired reports whether the redirect is no longer served
+func (d Redirect) Expired() bool {
+	return time.Now().After(d.ExpiresAt)
+}
+
+// Rename renames a repository whose bare repository has been moved to
+// newBarePath. With ttl > 0 the old name redirects to the new one until the
+// ttl has passed; redirects to the old name follow the rename.
+func (r *Registry) Rename(oldName, newName, newBarePath string, ttl time.Duration) error {
+	if err := ValidateName(newName); err != nil {
+		return err
+	}
+
+	mappings, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	var repo *RepoMapping
+	for i := range mappings.Repos {
+		switch mappings.Repos[i].Name {
+		case newName:
+			return fmt.Errorf("repository '%s' already exists", newName)
+		case oldName:
+			repo = &mappings.Repos[i]
+		}
+	}
+	if repo == nil {
+		return fmt.Errorf("repository '%s' not found", oldName)
+	}
+	repo.Name = newName
+	repo.BarePath = newBarePath
+
+	redirects := []Redirect{}
+	for _, d := range mappings.Redirects {
+		if d.Expired() || d.From == newName || d.From == oldName {
+			continue
+		}
+		if d.To == oldName {
+			d.To = newName
+		}
+		redirects = append(redirects, d)
+	}
+	if ttl > 0 {
+		redirects = append(redirects, Redirect{From: oldName, To: newName, ExpiresAt: time.Now().Add(ttl)})
+	}
+	mappings.Redirects = redirects
+
+	return r.save(mappings)
+}
+
+// ResolveRedirect returns the current name of a renamed repository if its
+// old name still redirects
+func (r *Registry) ResolveRedirect(name string) (string, bool) {
+	mappings, err := r.load()
+	if err != nil {
+		return "", false
+	}
+	for _, d := range mappings.Redirects {
+		if d.From == name && !d.Expired() {
+			return d.To, true
+		}
+	}
+	return "", false
+}
+
+// Redirects returns the redirects that are still served
+func (r *Registry) Redirects() ([]Redirect, error) {
+	mappings, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	active := []Redirect{}
+	for _, d := range mappings.Redirects {
+		if !d.Expired() {
+			active = append(active, d)
+		}
+	}
+	return active, nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRenameRedirects(t *testing.T) {
+	r := NewWithPath(filepath.Join(t.TempDir(), "mappings.yaml"))
+	if err := r.Add("api", "/src", "/repos/api.git"); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	if err := r.Add("web", "/web", "/repos/web.git"); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	if err := r.Rename("api", "web", "/repos/web.git", time.Hour); err == nil {
+		t.Error("Rename() onto an existing repository should fail")
+	}
+	if err := r.Rename("api", "team/api", "/repos/team/api.git", time.Hour); err != nil {
+		t.Fatalf("Rename() failed: %v", err)
+	}
+
+	repo, err := r.Find("team/api")
+	if err != nil || repo.BarePath != "/repos/team/api.git" || repo.SourcePath != "/src" {
+		t.Fatalf("Find(team/api) = %+v, %v", repo, err)
+	}
+	if r.Exists("api") {
+		t.Error("old name should no longer be registered")
+	}
+	if to, ok := r.ResolveRedirect("api"); !ok || to != "team/api" {
+		t.Errorf("ResolveRedirect(api) = %q, %v", to, ok)
+	}
+
+	// Renaming again moves existing redirects along
+	if err := r.Rename("team/api", "team/client-api", "/repos/team/client-api.git", time.Hour); err != nil {
+		t.Fatalf("Rename() failed: %v", err)
+	}
+	if to, _ := r.ResolveRedirect("api"); to != "team/client-api" {
+		t.Errorf("ResolveRedirect(api) = %q, want team/client-api", to)
+	}
+
+	// A new repository with the old name replaces the redirect
+	if err := r.Add("api", "/src2", "/repos/api.git"); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	if _, ok := r.ResolveRedirect("api"); ok {
+		t.Error("redirect should be dropped when the name is reused")
+	}
+
+	// Without a ttl there is no redirect
+	if err := r.Rename("web", "site", "/repos/site.git", 0); err != nil {
+		t.Fatalf("Rename() failed: %v", err)
+	}
+	if _, ok := r.ResolveRedirect("web"); ok {
+		t.Error("Rename() without ttl should not redirect")
+	}
+}
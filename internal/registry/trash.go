@@ -0,0 +1,118 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"fmt"
+	"time"
+)
+
+// TrashedRepo is a removed repository whose bare repository waits in the
+// trash until it is restored or purged
+type TrashedRepo struct {
+	RepoMapping `yaml:",inline"`
+	TrashPath   string    `yaml:"trash_path"`
+	DeletedAt   time.Time `yaml:"deleted_at"`
+}
+
+// Trash moves the registry entry of name to the trash list; its bare
+// repository has been moved to trashPath
+func (r *Registry) Trash(name, trashPath string) (*TrashedRepo, error) {
+	mappings, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, repo := range mappings.Repos {
+		if repo.Name != name {
+			continue
+		}
+		trashed := TrashedRepo{RepoMapping: repo, TrashPath: trashPath, DeletedAt: time.Now()}
+		mappings.Repos = append(mappings.Repos[:i], mappings.Repos[i+1:]...)
+		mappings.Trash = append(mappings.Trash, trashed)
+		if err := r.save(mappings); err != nil {
+			return nil, err
+		}
+		return &trashed, nil
+	}
+
+	return nil, fmt.Errorf("repository '%s' not found", name)
+}
+
+// TrashList returns the trashed repositories, oldest first
+func (r *Registry) TrashList() ([]TrashedRepo, error) {
+	mappings, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	return mappings.Trash, nil
+}
+
+// Untrash registers the trashed repository stored at trashPath again as
+// name, with its bare repository moved back to barePath
+func (r *Registry) Untrash(trashPath, name, barePath string) (*RepoMapping, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	mappings, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, repo := range mappings.Repos {
+		if repo.Name == name {
+			return nil, fmt.Errorf("repository '%s' already exists", name)
+		}
+	}
+
+	for i, trashed := range mappings.Trash {
+		if trashed.TrashPath != trashPath {
+			continue
+		}
+		repo := trashed.RepoMapping
+		repo.Name = name
+		repo.BarePath = barePath
+		mappings.Trash = append(mappings.Trash[:i], mappings.Trash[i+1:]...)
+		mappings.Repos = append(mappings.Repos, repo)
+		if err := r.save(mappings); err != nil {
+			return nil, err
+		}
+		return &repo, nil
+	}
+
+	return nil, fmt.Errorf("no trashed repository at '%s'", trashPath)
+}
+
+// DropTrashed forgets the trashed repository stored at trashPath
+func (r *Registry) DropTrashed(trashPath string) error {
+	mappings, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	for i, trashed := range mappings.Trash {
+		if trashed.TrashPath == trashPath {
+			mappings.Trash = append(mappings.Trash[:i], mappings.Trash[i+1:]...)
+			return r.save(mappings)
+		}
+	}
+	return fmt.Errorf("no trashed repository at '%s'", trashPath)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTrashAndUntrash(t *testing.T) {
+	r := NewWithPath(filepath.Join(t.TempDir(), "mappings.yaml"))
+	if err := r.Add("team/api", "/src", "/repos/team/api.git"); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	trashed, err := r.Trash("team/api", "/trash/team~api.1.git")
+	if err != nil {
+		t.Fatalf("Trash() failed: %v", err)
+	}
+	if trashed.SourcePath != "/src" || trashed.DeletedAt.IsZero() {
+		t.Errorf("Trash() = %+v", trashed)
+	}
+	if r.Exists("team/api") {
+		t.Error("trashed repository should no longer be registered")
+	}
+	if _, err := r.Trash("team/api", "/trash/x.git"); err == nil {
+		t.Error("Trash() of an unknown repository should fail")
+	}
+
+	list, err := r.TrashList()
+	if err != nil || len(list) != 1 || list[0].Name != "team/api" {
+		t.Fatalf("TrashList() = %+v, %v", list, err)
+	}
+
+	// The name is taken again: restoring under it fails
+	if err := r.Add("team/api", "/other", "/repos/team/api.git"); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	if _, err := r.Untrash(trashed.TrashPath, "team/api", "/repos/team/api.git"); err == nil {
+		t.Error("Untrash() onto an existing repository should fail")
+	}
+
+	repo, err := r.Untrash(trashed.TrashPath, "team/api-old", "/repos/team/api-old.git")
+	if err != nil {
+		t.Fatalf("Untrash() failed: %v", err)
+	}
+	if repo.SourcePath != "/src" || repo.BarePath != "/repos/team/api-old.git" {
+		t.Errorf("Untrash() = %+v", repo)
+	}
+	if !r.Exists("team/api-old") {
+		t.Error("restored repository should be registered")
+	}
+	if list, _ := r.TrashList(); len(list) != 0 {
+		t.Errorf("TrashList() after Untrash() = %+v", list)
+	}
+
+	if _, err := r.Trash("team/api-old", "/trash/team~api-old.2.git"); err != nil {
+		t.Fatalf("Trash() failed: %v", err)
+	}
+	if err := r.DropTrashed("/trash/team~api-old.2.git"); err != nil {
+		t.Fatalf("DropTrashed() failed: %v", err)
+	}
+	if list, _ := r.TrashList(); len(list) != 0 {
+		t.Errorf("TrashList() after DropTrashed() = %+v", list)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package repos creates and removes repositories. 'lgh add', 'lgh remove',
+// push-to-create and the management API share it, so that every path
+// checks, registers, cleans up and publishes events the same way.
+package repos
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/event"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/trash"
+)
+
+// ErrExists is returned by Create when the name or bare repository is taken
+var ErrExists = errors.New("already exists")
+
+// createMu serializes Create so concurrent requests create a repository once
+var createMu sync.Mutex
+
+// CreateOptions describes a repository to create
+type CreateOptions struct {
+	// Name may include a namespace ("team/app")
+	Name string
+	// SourcePath is the local working repository; empty for repositories
+	// created over the network
+	SourcePath  string
+	CreatedBy   string
+	Description string
+	// URL is the clone URL reported in the repo.added event
+	URL string
+	// Via tells event consumers how the repository was created ("push",
+	// "api"); empty for 'lgh add'
+	Via string
+}
+
+// Create initializes and registers a bare repository and publishes repo.added
+func Create(reg *registry.Registry, cfg *config.Config, opts CreateOptions) (*registry.RepoMapping, error) {
+	if err := registry.ValidateName(opts.Name); err != nil {
+		return nil, err
+	}
+
+	createMu.Lock()
+	defer createMu.Unlock()
+
+	if reg.Exists(opts.Name) {
+		return nil, fmt.Errorf("repository '%s' %w", opts.Name, ErrExists)
+	}
+	barePath := registry.BarePathFor(cfg.ReposDir, opts.Name)
+	if !registry.IsPathSafe(cfg.ReposDir, barePath) {
+		return nil, fmt.Errorf("security error: bare repository path '%s' is outside of repos directory '%s'",
+			barePath, cfg.ReposDir)
+	}
+	if _, err := os.Stat(barePath); err == nil {
+		return nil, fmt.Errorf("bare repository at %s %w", barePath, ErrExists)
+	}
+
+	if err := git.InitBareRepo(barePath); err != nil {
+		// Remove what init left behind, or the name stays taken
+		_ = os.RemoveAll(barePath)
+		RemoveEmptyNamespaceDirs(cfg.ReposDir, barePath)
+		return nil, err
+	}
+	repo := registry.RepoMapping{
+		Name:        opts.Name,
+		SourcePath:  opts.SourcePath,
+		BarePath:    barePath,
+		CreatedAt:   time.Now(),
+		CreatedBy:   opts.CreatedBy,
+		Description: opts.Description,
+	}
+	if err := reg.AddMapping(repo); err != nil {
+		// Cleanup: remove bare repo if registration fails
+		_ = os.RemoveAll(barePath)
+		RemoveEmptyNamespaceDirs(cfg.ReposDir, barePath)
+		return nil, fmt.Errorf("failed to register repository: %w", err)
+	}
+
+	payload := map[string]interface{}{
+		"bare": barePath,
+		"url":  opts.URL,
+	}
+	if opts.SourcePath != "" {
+		payload["source"] = opts.SourcePath
+	}
+	if opts.CreatedBy != "" {
+		payload["created_by"] = opts.CreatedBy
+	}
+	if opts.Via != "" {
+		payload["via"] = opts.Via
+	}
+	event.Publish(event.RepoAdded, opts.Name, payload)
+
+	return &repo, nil
+}
+
+// RemoveMode decides what happens to the bare repository of a removed repository
+type RemoveMode int
+
+const (
+	// MoveToTrash keeps the repository restorable with 'lgh trash restore'
+	MoveToTrash RemoveMode = iota
+	// Purge deletes the bare repository
+	Purge
+	// KeepBare only unregisters the repository
+	KeepBare
+)
+
+// Removal is the outcome of Remove
+type Removal struct {
+	// TrashPath is where MoveToTrash put the bare repository
+	TrashPath string
+	// Deleted is false when Purge left a path that is not a bare repository alone
+	Deleted bool
+}
+
+// Remove unregisters repo, handles its bare repository according to mode
+// and publishes repo.removed. via is reported in the event like in Create.
+func Remove(reg *registry.Registry, cfg *config.Config, repo *registry.RepoMapping, mode RemoveMode, via string) (*Removal, error) {
+	// SECURITY: Validate BarePath is within the configured ReposDir
+	if !registry.IsPathSafe(cfg.ReposDir, repo.BarePath) {
+		return nil, fmt.Errorf("security error: bare repository path '%s' is outside of repos directory '%s'",
+			repo.BarePath, cfg.ReposDir)
+	}
+
+	removal := &Removal{}
+	switch mode {
+	case MoveToTrash:
+		// The registry entry moves to the trash list with the bare repository
+		trashed, err := trash.Move(reg, cfg, repo)
+		if err != nil {
+			return nil, err
+		}
+		removal.TrashPath = trashed.TrashPath
+		RemoveEmptyNamespaceDirs(cfg.ReposDir, repo.BarePath)
+
+	case Purge:
+		// Double-check the path is a git bare repository before deleting
+		if git.IsBareRepo(repo.BarePath) {
+			if err := os.RemoveAll(repo.BarePath); err != nil {
+				return nil, fmt.Errorf("failed to delete bare repository: %w", err)
+			}
+			removal.Deleted = true
+			RemoveEmptyNamespaceDirs(cfg.ReposDir, repo.BarePath)
+		}
+		fallthrough
+
+	case KeepBare:
+		if err := reg.Remove(repo.Name); err != nil {
+			return nil, fmt.Errorf("failed to remove from registry: %w", err)
+		}
+	}
+
+	payload := map[string]interface{}{
+		"source": repo.SourcePath,
+		"bare":   repo.BarePath,
+	}
+	if removal.TrashPath != "" {
+		payload["trash"] = removal.TrashPath
+	}
+	if via != "" {
+		payload["via"] = via
+	}
+	event.Publish(event.RepoRemoved, repo.Name, payload)
+
+	return removal, nil
+}
+
+// RemoveEmptyNamespaceDirs deletes the namespace directories of a removed
+// repository (repos/team/sub/app.git) once they are empty
+func RemoveEmptyNamespaceDirs(reposDir, barePath string) {
+	base := filepath.Clean(reposDir)
+	for dir := filepath.Dir(barePath); dir != base && registry.IsPathSafe(base, dir); dir = filepath.Dir(dir) {
+		// os.Remove fails on non-empty directories
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package repos
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/event"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+var (
+	eventsMu sync.Mutex
+	events   []event.Event
+)
+
+func init() {
+	event.Subscribe(func(evt event.Event) {
+		eventsMu.Lock()
+		defer eventsMu.Unlock()
+		events = append(events, evt)
+	})
+}
+
+func lastEvent(t *testing.T) event.Event {
+	t.Helper()
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	if len(events) == 0 {
+		t.Fatal("no event published")
+	}
+	return events[len(events)-1]
+}
+
+func setup(t *testing.T) (*registry.Registry, *config.Config) {
+	t.Helper()
+	if _, err := git.CheckGitInstalled(); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	dir := t.TempDir()
+	cfg := &config.Config{
+		DataDir:  dir,
+		ReposDir: filepath.Join(dir, "repos"),
+	}
+	return registry.NewWithPath(filepath.Join(dir, "mappings.yaml")), cfg
+}
+
+func TestCreate(t *testing.T) {
+	reg, cfg := setup(t)
+
+	repo, err := Create(reg, cfg, CreateOptions{Name: "team/app", CreatedBy: "alice", Description: "demo", Via: "api"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if !git.IsBareRepo(repo.BarePath) {
+		t.Errorf("%s is not a bare repository", repo.BarePath)
+	}
+	found, err := reg.Find("team/app")
+	if err != nil || found.CreatedBy != "alice" || found.Description != "demo" {
+		t.Errorf("Find() = %+v, %v", found, err)
+	}
+	if evt := lastEvent(t); evt.Type != event.RepoAdded || evt.RepoName != "team/app" || evt.Payload["via"] != "api" {
+		t.Errorf("event = %+v", evt)
+	}
+
+	if _, err := Create(reg, cfg, CreateOptions{Name: "team/app"}); !errors.Is(err, ErrExists) {
+		t.Errorf("Create() of an existing repository = %v, want ErrExists", err)
+	}
+	if _, err := Create(reg, cfg, CreateOptions{Name: "../app"}); err == nil {
+		t.Error("Create() should reject invalid names")
+	}
+}
+
+func TestCreateCleansUpFailedInit(t *testing.T) {
+	reg, cfg := setup(t)
+
+	// A directory where a managed hook goes makes hook installation fail
+	// after git init created the repository
+	template := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(template, "hooks", git.ManagedHooks[0], "x"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_TEMPLATE_DIR", template)
+
+	if _, err := Create(reg, cfg, CreateOptions{Name: "team/app"}); err == nil {
+		t.Fatal("Create() should fail when hooks cannot be installed")
+	}
+	if _, err := os.Stat(filepath.Join(cfg.ReposDir, "team")); !os.IsNotExist(err) {
+		t.Error("half-created repository should be removed")
+	}
+
+	_ = os.Unsetenv("GIT_TEMPLATE_DIR")
+	if _, err := Create(reg, cfg, CreateOptions{Name: "team/app"}); err != nil {
+		t.Errorf("Create() after a failed attempt = %v", err)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	reg, cfg := setup(t)
+
+	for _, tt := range []struct {
+		name string
+		mode RemoveMode
+	}{
+		{"team/trashed", MoveToTrash},
+		{"team/purged", Purge},
+		{"kept", KeepBare},
+	} {
+		repo, err := Create(reg, cfg, CreateOptions{Name: tt.name})
+		if err != nil {
+			t.Fatalf("Create(%s) failed: %v", tt.name, err)
+		}
+		removal, err := Remove(reg, cfg, repo, tt.mode, "api")
+		if err != nil {
+			t.Fatalf("Remove(%s) failed: %v", tt.name, err)
+		}
+		if reg.Exists(tt.name) {
+			t.Errorf("%s is still registered", tt.name)
+		}
+		if evt := lastEvent(t); evt.Type != event.RepoRemoved || evt.RepoName != tt.name {
+			t.Errorf("event = %+v", evt)
+		}
+
+		_, statErr := os.Stat(repo.BarePath)
+		switch tt.mode {
+		case MoveToTrash:
+			if removal.TrashPath == "" || !git.IsBareRepo(removal.TrashPath) || !os.IsNotExist(statErr) {
+				t.Errorf("MoveToTrash: removal = %+v, bare stat = %v", removal, statErr)
+			}
+		case Purge:
+			if !removal.Deleted || !os.IsNotExist(statErr) {
+				t.Errorf("Purge: removal = %+v, bare stat = %v", removal, statErr)
+			}
+		case KeepBare:
+			if removal.Deleted || removal.TrashPath != "" || statErr != nil {
+				t.Errorf("KeepBare: removal = %+v, bare stat = %v", removal, statErr)
+			}
+		}
+	}
+
+	// Empty namespace directories go with the last repository in them
+	if _, err := os.Stat(filepath.Join(cfg.ReposDir, "team")); !os.IsNotExist(err) {
+		t.Error("empty namespace directory should be removed")
+	}
+}
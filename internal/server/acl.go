@@ -0,0 +1,191 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/lfs"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+)
+
+// SetRegistry enables per-repository access lists from the given registry
+func (a *AuthMiddleware) SetRegistry(reg *registry.Registry) {
+	a.registry = reg
+}
+
+// authorizeRepo checks the repository access list for an authenticated user.
+// Repositories without an access list are open to every authenticated user.
+// It writes an error response and returns false if access is denied.
+func (a *AuthMiddleware) authorizeRepo(w http.ResponseWriter, r *http.Request, username string) bool {
+	if a.registry == nil {
+		return true
+	}
+
+	name := a.requestRepo(r)
+	if name == "" {
+		return true
+	}
+
+	status, err := checkRepoAccess(a.registry, a.users, username, name, requiredPermission(r))
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return false
+	}
+	return true
+}
+
+// ownerContextKey marks requests authenticated as the server owner (the
+// config.yaml account or a token not bound to a user), who bypass access lists
+type ownerContextKey struct{}
+
+// canRead reports whether the authenticated user of r may read repo. It is
+// used where one request covers several repositories, like listing them.
+func (a *AuthMiddleware) canRead(r *http.Request, repo *registry.RepoMapping) bool {
+	// Reading is open to everyone when authentication is disabled
+	if a == nil || !repo.Restricted() || a.isOwner(r) {
+		return true
+	}
+	username, ok := users.FromContext(r.Context())
+	if !ok {
+		return false
+	}
+	return repo.PermissionFor(username, a.groupsOf(username)) != ""
+}
+
+// canCreate reports whether the authenticated user of r may create the
+// repository name through the API. Users need a push-to-create rule.
+// Nobody may without authentication.
+func (a *AuthMiddleware) canCreate(r *http.Request, reg *registry.Registry, name string) (bool, error) {
+	if a == nil {
+		return false, nil
+	}
+	if a.isOwner(r) {
+		return true, nil
+	}
+	username, ok := users.FromContext(r.Context())
+	if !ok {
+		return false, nil
+	}
+	return reg.CanCreate(name, username, a.groupsOf(username))
+}
+
+// canManage reports whether the authenticated user of r may change or delete
+// repo: admins of a restricted repository, or the user who created an
+// unrestricted one. Nobody may without authentication.
+func (a *AuthMiddleware) canManage(r *http.Request, repo *registry.RepoMapping) bool {
+	if a == nil {
+		return false
+	}
+	if a.isOwner(r) {
+		return true
+	}
+	username, ok := users.FromContext(r.Context())
+	if !ok {
+		return false
+	}
+	if repo.Restricted() {
+		return repo.PermissionFor(username, a.groupsOf(username)).Includes(registry.PermAdmin)
+	}
+	return repo.CreatedBy != "" && repo.CreatedBy == username
+}
+
+// isOwner reports whether r is authenticated as the server owner
+func (a *AuthMiddleware) isOwner(r *http.Request) bool {
+	owner, _ := r.Context().Value(ownerContextKey{}).(bool)
+	return owner
+}
+
+// groupsOf returns the groups of a user account
+func (a *AuthMiddleware) groupsOf(username string) []string {
+	if a.users == nil {
+		return nil
+	}
+	u, err := a.users.Find(username)
+	if err != nil {
+		return nil
+	}
+	return u.Groups
+}
+
+// resolveRedirect returns the new name if name is the old name of a renamed repository
+func (a *AuthMiddleware) resolveRedirect(name string) (string, bool) {
+	if a.registry == nil || name == "" {
+		return "", false
+	}
+	return a.registry.ResolveRedirect(name)
+}
+
+// checkRepoAccess checks whether username has the required permission on a
+// repository. On denial it returns the HTTP status to report: 404 if the user
+// has no access at all (so the repository is not revealed), 403 otherwise.
+// Shared by the HTTP and SSH transports.
+func checkRepoAccess(reg *registry.Registry, userStore *users.Store, username, name string, required registry.Permission) (int, error) {
+	repo, err := reg.Find(name)
+	if err != nil {
+		// The old name of a renamed repository has the access list of the new one
+		if newName, ok := reg.ResolveRedirect(name); ok {
+			repo, err = reg.Find(newName)
+		}
+	}
+	if err != nil || !repo.Restricted() {
+		// Unknown repositories are left to the backend (404)
+		return 0, nil
+	}
+
+	var groups []string
+	if userStore != nil {
+		if u, err := userStore.Find(username); err == nil {
+			groups = u.Groups
+		}
+	}
+
+	perm := repo.PermissionFor(username, groups)
+	if perm == "" {
+		// Do not reveal that the repository exists
+		return http.StatusNotFound, fmt.Errorf("Repository not found: %s.git", name)
+	}
+
+	if !perm.Includes(required) {
+		return http.StatusForbidden, fmt.Errorf("Forbidden: %s has %s access to '%s', %s required", username, perm, name, required)
+	}
+
+	return 0, nil
+}
+
+// requiredPermission maps a request to the repository permission it needs.
+// Push detection is shared with the git backend; LFS uploads count as pushes.
+func requiredPermission(r *http.Request) registry.Permission {
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			return registry.PermRead
+		}
+		return registry.PermAdmin
+	}
+	if git.IsPushRequest(r) || lfs.IsWriteRequest(r) {
+		return registry.PermWrite
+	}
+	return registry.PermRead
+}
@@ -0,0 +1,309 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+const (
+	// apiDefaultPerPage is the page size of paginated API lists
+	apiDefaultPerPage = 30
+	// apiMaxPerPage caps the per_page query parameter
+	apiMaxPerPage = 100
+)
+
+// apiRepo is a repository in /api/repos responses
+type apiRepo struct {
+	Name          string    `json:"name"`
+	Namespace     string    `json:"namespace"`
+	Description   string    `json:"description"`
+	CloneURL      string    `json:"clone_url"`
+	SourcePath    string    `json:"source_path,omitempty"` // owner only
+	CreatedAt     time.Time `json:"created_at"`
+	CreatedBy     string    `json:"created_by"`
+	Restricted    bool      `json:"restricted"`
+	DefaultBranch string    `json:"default_branch"`
+}
+
+// apiRepoDetail is the /api/repos/{repo} response
+type apiRepoDetail struct {
+	apiRepo
+	// Head is the last commit of the default branch, null for empty repositories
+	Head *git.Commit `json:"head"`
+}
+
+// apiBranch is a branch in /api/repos/{repo}/branches responses
+type apiBranch struct {
+	Name      string `json:"name"`
+	SHA       string `json:"sha"`
+	Default   bool   `json:"default"`
+	Protected bool   `json:"protected"`
+}
+
+// apiTag is a tag in /api/repos/{repo}/tags responses
+type apiTag struct {
+	Name string `json:"name"`
+	// SHA is the tagged commit; TagSHA the tag object of annotated tags
+	SHA    string `json:"sha"`
+	TagSHA string `json:"tag_sha,omitempty"`
+}
+
+// isAPIRead reports whether r only reads through the API
+func isAPIRead(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	// Commit statuses predate the read-only API and stay admin-only
+	return !strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/status")
+}
+
+// writeJSON sends v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeAPIError sends {"error": msg}
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// queryPaths reads file path filters given as repeated and/or
+// comma-separated query values. It refuses "." and ".." segments.
+func queryPaths(values []string) ([]string, bool) {
+	var paths []string
+	for _, value := range values {
+		for _, p := range strings.Split(value, ",") {
+			p = strings.Trim(p, "/")
+			if p == "" {
+				continue
+			}
+			for _, segment := range strings.Split(p, "/") {
+				if segment == "" || segment == "." || segment == ".." || strings.ContainsRune(segment, 0) {
+					return nil, false
+				}
+			}
+			paths = append(paths, p)
+		}
+	}
+	return paths, true
+}
+
+// cloneURL returns the HTTP clone URL of a repository as seen by the client
+func (s *Server) cloneURL(r *http.Request, name string) string {
+	base := s.cfg.BaseURL(s.cfg.BindAddress)
+	if r.Host != "" {
+		base = s.cfg.Scheme() + "://" + r.Host
+	}
+	return base + registry.URLPath(name)
+}
+
+func (s *Server) newAPIRepo(r *http.Request, repo *registry.RepoMapping) apiRepo {
+	defaultBranch, _ := git.GetDefaultBranch(repo.BarePath)
+	result := apiRepo{
+		Name:          repo.Name,
+		Namespace:     repo.Namespace(),
+		Description:   repo.Description,
+		CloneURL:      s.cloneURL(r, repo.Name),
+		CreatedAt:     repo.CreatedAt,
+		CreatedBy:     repo.CreatedBy,
+		Restricted:    repo.Restricted(),
+		DefaultBranch: defaultBranch,
+	}
+	if s.auth.isOwner(r) {
+		result.SourcePath = repo.SourcePath
+	}
+	return result
+}
+
+// findAPIRepo looks up the repository of an API request. The old name of a
+// renamed repository redirects to the same endpoint under the new name, with
+// 308 for changes so that clients keep the method and body.
+func (s *Server) findAPIRepo(w http.ResponseWriter, r *http.Request, name string, parts []string) (*registry.RepoMapping, bool) {
+	reg := registry.New()
+	repo, err := reg.Find(name)
+	if err == nil {
+		return repo, true
+	}
+
+	if newName, ok := reg.ResolveRedirect(name); ok {
+		target := "/api/repos/" + strings.Join(append([]string{newName}, parts...), "/")
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		status := http.StatusMovedPermanently
+		if !isAPIRead(r) {
+			status = http.StatusPermanentRedirect
+		}
+		http.Redirect(w, r, target, status)
+		return nil, false
+	}
+
+	writeAPIError(w, http.StatusNotFound, fmt.Sprintf("repository '%s' not found", name))
+	return nil, false
+}
+
+// handleAPIRepoList serves GET /api/repos: the repositories the caller can read
+func (s *Server) handleAPIRepoList(w http.ResponseWriter, r *http.Request) {
+	repos, err := registry.New().List()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to list repositories")
+		return
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Name < repos[j].Name })
+
+	result := []apiRepo{}
+	for i := range repos {
+		if s.auth.canRead(r, &repos[i]) {
+			result = append(result, s.newAPIRepo(r, &repos[i]))
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleAPIRepo serves GET /api/repos/{repo}
+func (s *Server) handleAPIRepo(w http.ResponseWriter, r *http.Request, repo *registry.RepoMapping) {
+	detail := apiRepoDetail{apiRepo: s.newAPIRepo(r, repo)}
+	if detail.DefaultBranch != "" {
+		if commit, err := git.GetLastCommit(repo.BarePath, detail.DefaultBranch); err == nil {
+			detail.Head = commit
+		}
+	}
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// handleAPIBranches serves GET /api/repos/{repo}/branches
+func (s *Server) handleAPIBranches(w http.ResponseWriter, repo *registry.RepoMapping) {
+	names, err := git.GetBranches(repo.BarePath)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to list branches")
+		return
+	}
+	refs, err := git.GetRefs(repo.BarePath)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to read refs")
+		return
+	}
+	defaultBranch, _ := git.GetDefaultBranch(repo.BarePath)
+
+	branches := []apiBranch{}
+	for _, name := range names {
+		branches = append(branches, apiBranch{
+			Name:      name,
+			SHA:       refs["refs/heads/"+name],
+			Default:   name == defaultBranch,
+			Protected: len(repo.ProtectionFor(name)) > 0,
+		})
+	}
+	writeJSON(w, http.StatusOK, branches)
+}
+
+// handleAPITags serves GET /api/repos/{repo}/tags
+func (s *Server) handleAPITags(w http.ResponseWriter, repo *registry.RepoMapping) {
+	list, err := git.GetTags(repo.BarePath)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to list tags")
+		return
+	}
+
+	tags := make([]apiTag, 0, len(list))
+	for _, tag := range list {
+		tags = append(tags, apiTag{Name: tag.Name, SHA: tag.SHA, TagSHA: tag.TagSHA})
+	}
+	writeJSON(w, http.StatusOK, tags)
+}
+
+// handleAPICommits serves GET /api/repos/{repo}/commits?ref=&path=&page=&per_page=,
+// newest first. Further pages are announced in a Link header.
+func (s *Server) handleAPICommits(w http.ResponseWriter, r *http.Request, repo *registry.RepoMapping) {
+	query := r.URL.Query()
+	page, perPage, ok := apiPagination(query)
+	if !ok {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("page must be >= 1 and per_page between 1 and %d", apiMaxPerPage))
+		return
+	}
+
+	ref := query.Get("ref")
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	// One extra commit tells whether there is a next page
+	commits, err := git.ListCommits(repo.BarePath, ref, query.Get("path"), (page-1)*perPage, perPage+1)
+	if errors.Is(err, git.ErrNotFound) {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("ref '%s' not found", ref))
+		return
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var links []string
+	if len(commits) > perPage {
+		commits = commits[:perPage]
+		links = append(links, apiPageLink(r, page+1, "next"))
+	}
+	if page > 1 {
+		links = append(links, apiPageLink(r, page-1, "prev"))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+	writeJSON(w, http.StatusOK, commits)
+}
+
+// apiPagination reads the page and per_page query parameters
+func apiPagination(query url.Values) (page, perPage int, ok bool) {
+	page, perPage = 1, apiDefaultPerPage
+	var err error
+	if v := query.Get("page"); v != "" {
+		if page, err = strconv.Atoi(v); err != nil || page < 1 {
+			return 0, 0, false
+		}
+	}
+	if v := query.Get("per_page"); v != "" {
+		if perPage, err = strconv.Atoi(v); err != nil || perPage < 1 || perPage > apiMaxPerPage {
+			return 0, 0, false
+		}
+	}
+	return page, perPage, true
+}
+
+// apiPageLink formats a Link header entry for another page of r
+func apiPageLink(r *http.Request, page int, rel string) string {
+	query := r.URL.Query()
+	query.Set("page", strconv.Itoa(page))
+	return fmt.Sprintf(`<%s?%s>; rel="%s"`, r.URL.Path, query.Encode(), rel)
+}
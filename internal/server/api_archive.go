@@ -0,0 +1,125 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/slog"
+)
+
+// archiveContentTypes are the content types of the git archive formats
+var archiveContentTypes = map[string]string{
+	"tar.gz": "application/gzip",
+	"zip":    "application/zip",
+}
+
+// splitArchiveName splits "feature/x.tar.gz" into the ref and the archive
+// suffix; refs may contain slashes
+func splitArchiveName(segments []string) (ref, suffix string, ok bool) {
+	name := strings.Join(segments, "/")
+	for suffix := range git.ArchiveFormats {
+		if ref, ok := strings.CutSuffix(name, suffix); ok && git.ValidRef(ref) {
+			return ref, suffix, true
+		}
+	}
+	return "", "", false
+}
+
+// archivePrefix checks the ?prefix= directory of an archive. By default
+// files are under "<repo>-<ref>/"; an explicitly empty prefix puts them at the top.
+func archivePrefix(r *http.Request, repo *registry.RepoMapping, ref string) (string, bool) {
+	if !r.URL.Query().Has("prefix") {
+		return path.Base(repo.Name) + "-" + strings.ReplaceAll(ref, "/", "-") + "/", true
+	}
+	prefix := strings.Trim(r.URL.Query().Get("prefix"), "/")
+	if prefix == "" {
+		return "", true
+	}
+	// SECURITY: Archives must not extract outside of the directory they are unpacked in
+	if _, ok := queryPaths([]string{prefix}); !ok || strings.Contains(prefix, ",") {
+		return "", false
+	}
+	return prefix + "/", true
+}
+
+// handleAPIArchive serves GET /api/repos/{repo}/archive/{ref}.tar.gz and
+// .zip, streamed from git archive. ?path= selects subdirectories and
+// ?prefix= sets the top-level directory.
+func (s *Server) handleAPIArchive(w http.ResponseWriter, r *http.Request, repo *registry.RepoMapping, segments []string) {
+	ref, suffix, ok := splitArchiveName(segments)
+	if !ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid archive, expected /archive/{ref}.tar.gz or /archive/{ref}.zip")
+		return
+	}
+	sha, err := git.ResolveCommit(repo.BarePath, ref)
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("ref '%s' not found", ref))
+		return
+	}
+
+	prefix, ok := archivePrefix(r, repo, ref)
+	if !ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid prefix")
+		return
+	}
+	paths, ok := queryPaths(r.URL.Query()["path"])
+	if !ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+	for _, p := range paths {
+		if _, err := git.ObjectType(repo.BarePath, sha, p); err != nil {
+			writeAPIError(w, http.StatusNotFound, fmt.Sprintf("path '%s' not found in '%s'", p, ref))
+			return
+		}
+	}
+
+	// Archives are as expensive as clones: share their process limit
+	if s.gitLimiter != nil {
+		release, err := s.gitLimiter.Acquire(r.Context())
+		if err != nil {
+			writeAPIError(w, http.StatusServiceUnavailable, "server busy, try again later")
+			return
+		}
+		defer release()
+	}
+
+	format := git.ArchiveFormats[suffix]
+	filename := path.Base(repo.Name) + "-" + strings.ReplaceAll(ref, "/", "-") + suffix
+	w.Header().Set("Content-Type", archiveContentTypes[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, strings.ReplaceAll(filename, `"`, "")))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if r.Method == http.MethodHead {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// The status is sent with the first bytes, so a failure can only be logged
+	if err := git.Archive(r.Context(), repo.BarePath, format, prefix, sha, paths, w); err != nil {
+		slog.Error("Archive failed", map[string]interface{}{"repo": repo.Name, "ref": ref, "error": err.Error()})
+	}
+}
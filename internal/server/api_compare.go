@@ -0,0 +1,157 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+const (
+	// apiMaxCompareCommits caps the commits listed by a comparison
+	apiMaxCompareCommits = 250
+	// apiMaxCompareFiles caps the files listed by a comparison
+	apiMaxCompareFiles = 1000
+	// apiMaxPatchSize caps ?format=patch responses
+	apiMaxPatchSize = 5 << 20
+)
+
+// apiCompare is the /api/repos/{repo}/compare/{base}...{head} response
+type apiCompare struct {
+	Base      string `json:"base"`
+	Head      string `json:"head"`
+	BaseSHA   string `json:"base_sha"`
+	HeadSHA   string `json:"head_sha"`
+	MergeBase string `json:"merge_base"`
+	// AheadBy counts the commits of head missing from base, BehindBy the reverse
+	AheadBy  int `json:"ahead_by"`
+	BehindBy int `json:"behind_by"`
+	// Commits are the newest apiMaxCompareCommits of the AheadBy commits
+	Commits        []git.Commit   `json:"commits"`
+	Files          []git.FileDiff `json:"files"`
+	FilesTruncated bool           `json:"files_truncated"`
+	Additions      int            `json:"additions"`
+	Deletions      int            `json:"deletions"`
+}
+
+// compareRefs splits "base...head"; refs may contain slashes
+func compareRefs(segments []string) (base, head string, ok bool) {
+	base, head, ok = strings.Cut(strings.Join(segments, "/"), "...")
+	if !ok || !git.ValidRef(base) || !git.ValidRef(head) {
+		return "", "", false
+	}
+	return base, head, true
+}
+
+// handleAPICompare serves GET /api/repos/{repo}/compare/{base}...{head}:
+// what head changes since it diverged from base, as JSON or with
+// ?format=patch as a unified diff. ?paths= limits the comparison to paths.
+func (s *Server) handleAPICompare(w http.ResponseWriter, r *http.Request, repo *registry.RepoMapping, segments []string) {
+	base, head, ok := compareRefs(segments)
+	if !ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid comparison, expected /compare/{base}...{head}")
+		return
+	}
+	paths, ok := queryPaths(r.URL.Query()["paths"])
+	if !ok {
+		writeAPIError(w, http.StatusBadRequest, "invalid path in paths")
+		return
+	}
+
+	result := apiCompare{Base: base, Head: head}
+	var err error
+	if result.BaseSHA, err = git.ResolveCommit(repo.BarePath, base); err != nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("ref '%s' not found", base))
+		return
+	}
+	if result.HeadSHA, err = git.ResolveCommit(repo.BarePath, head); err != nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("ref '%s' not found", head))
+		return
+	}
+	if result.MergeBase, err = git.MergeBase(repo.BarePath, result.BaseSHA, result.HeadSHA); errors.Is(err, git.ErrNotFound) {
+		writeAPIError(w, http.StatusUnprocessableEntity, fmt.Sprintf("'%s' and '%s' have no common history", base, head))
+		return
+	}
+
+	// Unified diff
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+	case "patch":
+		s.writeComparePatch(w, repo, result.MergeBase, result.HeadSHA, paths)
+		return
+	default:
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("unknown format '%s', expected json or patch", format))
+		return
+	}
+
+	if result.AheadBy, err = git.CountCommits(repo.BarePath, result.BaseSHA, result.HeadSHA); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to count commits")
+		return
+	}
+	if result.BehindBy, err = git.CountCommits(repo.BarePath, result.HeadSHA, result.BaseSHA); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to count commits")
+		return
+	}
+	if result.Commits, err = git.ListCommitRange(repo.BarePath, result.BaseSHA, result.HeadSHA, apiMaxCompareCommits); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to list commits")
+		return
+	}
+	if result.Files, err = git.DiffFiles(repo.BarePath, result.MergeBase, result.HeadSHA, paths); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to diff")
+		return
+	}
+	for _, file := range result.Files {
+		result.Additions += file.Additions
+		result.Deletions += file.Deletions
+	}
+	if len(result.Files) > apiMaxCompareFiles {
+		result.Files, result.FilesTruncated = result.Files[:apiMaxCompareFiles], true
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// writeComparePatch sends the unified diff between two commits, refusing
+// diffs larger than apiMaxPatchSize
+func (s *Server) writeComparePatch(w http.ResponseWriter, repo *registry.RepoMapping, base, head string, paths []string) {
+	patch, truncated, err := git.DiffPatch(repo.BarePath, base, head, paths, apiMaxPatchSize)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to diff")
+		return
+	}
+	if truncated {
+		writeAPIError(w, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("diff is larger than %d bytes, narrow it down with ?paths=", apiMaxPatchSize))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Length", strconv.Itoa(len(patch)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(patch)
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/repos"
+	"github.com/JoeGlenn1213/lgh/internal/slog"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+// apiMaxBodySize caps the JSON bodies of repository management requests
+const apiMaxBodySize = 64 << 10
+
+// apiRepoCreate is the POST /api/repos request body
+type apiRepoCreate struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// apiRepoUpdate is the PATCH /api/repos/{repo} request body. Omitted fields
+// are left unchanged.
+type apiRepoUpdate struct {
+	Description   *string `json:"description"`
+	DefaultBranch *string `json:"default_branch"`
+}
+
+// apiRepoRemoval is the DELETE /api/repos/{repo} response
+type apiRepoRemoval struct {
+	Name string `json:"name"`
+	// Trashed is set when the repository can be restored with 'lgh trash restore'
+	Trashed bool `json:"trashed"`
+	Purged  bool `json:"purged"`
+}
+
+// decodeAPIBody reads a JSON request body into v. It writes an error
+// response and returns false if the body is invalid.
+func decodeAPIBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, apiMaxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// handleAPICreateRepo serves POST /api/repos: it creates an empty bare
+// repository, like push-to-create but without pushing
+func (s *Server) handleAPICreateRepo(w http.ResponseWriter, r *http.Request) {
+	if s.cfg.ReadOnly {
+		writeAPIError(w, http.StatusForbidden, "server is read-only")
+		return
+	}
+
+	var req apiRepoCreate
+	if !decodeAPIBody(w, r, &req) {
+		return
+	}
+	if err := registry.ValidateName(req.Name); err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.DefaultBranch != "" && !git.ValidBranchName(req.DefaultBranch) {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid branch name '%s'", req.DefaultBranch))
+		return
+	}
+
+	reg := registry.New()
+	allowed, err := s.auth.canCreate(r, reg, req.Name)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to check create rules")
+		return
+	}
+	if !allowed {
+		writeAPIError(w, http.StatusForbidden, fmt.Sprintf("not allowed to create '%s'", req.Name))
+		return
+	}
+
+	username, _ := users.FromContext(r.Context())
+	repo, err := repos.Create(reg, s.cfg, repos.CreateOptions{
+		Name:        req.Name,
+		CreatedBy:   username,
+		Description: req.Description,
+		URL:         s.cloneURL(r, req.Name),
+		Via:         "api",
+	})
+	if errors.Is(err, repos.ErrExists) {
+		writeAPIError(w, http.StatusConflict, fmt.Sprintf("repository '%s' already exists", req.Name))
+		return
+	}
+	if err != nil {
+		ui.Warning("Failed to create repository '%s': %v", req.Name, err)
+		writeAPIError(w, http.StatusInternalServerError, "failed to create repository")
+		return
+	}
+	if req.DefaultBranch != "" {
+		if err := git.SetHead(repo.BarePath, req.DefaultBranch); err != nil {
+			ui.Warning("Failed to set default branch of '%s': %v", req.Name, err)
+		}
+	}
+
+	slog.WithComponent("server").Info("Repository created via API", map[string]interface{}{
+		"repo": req.Name,
+		"user": username,
+	})
+	w.Header().Set("Location", "/api/repos/"+req.Name)
+	writeJSON(w, http.StatusCreated, apiRepoDetail{apiRepo: s.newAPIRepo(r, repo)})
+}
+
+// handleAPIUpdateRepo serves PATCH /api/repos/{repo}
+func (s *Server) handleAPIUpdateRepo(w http.ResponseWriter, r *http.Request, repo *registry.RepoMapping) {
+	if s.cfg.ReadOnly {
+		writeAPIError(w, http.StatusForbidden, "server is read-only")
+		return
+	}
+	if !s.auth.canManage(r, repo) {
+		writeAPIError(w, http.StatusForbidden, fmt.Sprintf("not allowed to change '%s'", repo.Name))
+		return
+	}
+
+	var req apiRepoUpdate
+	if !decodeAPIBody(w, r, &req) {
+		return
+	}
+
+	if req.DefaultBranch != nil {
+		branch := *req.DefaultBranch
+		if !git.ValidBranchName(branch) {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid branch name '%s'", branch))
+			return
+		}
+		// Empty repositories may point HEAD at the branch of the first push
+		branches, err := git.GetBranches(repo.BarePath)
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, "failed to list branches")
+			return
+		}
+		if len(branches) > 0 && !slices.Contains(branches, branch) {
+			writeAPIError(w, http.StatusUnprocessableEntity, fmt.Sprintf("branch '%s' not found", branch))
+			return
+		}
+		if err := git.SetHead(repo.BarePath, branch); err != nil {
+			writeAPIError(w, http.StatusInternalServerError, "failed to set default branch")
+			return
+		}
+	}
+
+	reg := registry.New()
+	if req.Description != nil {
+		if err := reg.SetDescription(repo.Name, *req.Description); err != nil {
+			writeAPIError(w, http.StatusInternalServerError, "failed to update repository")
+			return
+		}
+		repo.Description = *req.Description
+	}
+
+	s.handleAPIRepo(w, r, repo)
+}
+
+// handleAPIDeleteRepo serves DELETE /api/repos/{repo}. The repository moves
+// to the trash unless ?purge=true is given.
+func (s *Server) handleAPIDeleteRepo(w http.ResponseWriter, r *http.Request, repo *registry.RepoMapping) {
+	if s.cfg.ReadOnly {
+		writeAPIError(w, http.StatusForbidden, "server is read-only")
+		return
+	}
+	if !s.auth.canManage(r, repo) {
+		writeAPIError(w, http.StatusForbidden, fmt.Sprintf("not allowed to delete '%s'", repo.Name))
+		return
+	}
+
+	mode := repos.MoveToTrash
+	if value := r.URL.Query().Get("purge"); value != "" {
+		purge, err := strconv.ParseBool(value)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, "invalid purge value")
+			return
+		}
+		if purge {
+			mode = repos.Purge
+		}
+	}
+
+	removal, err := repos.Remove(registry.New(), s.cfg, repo, mode, "api")
+	if err != nil {
+		ui.Warning("Failed to remove repository '%s': %v", repo.Name, err)
+		writeAPIError(w, http.StatusInternalServerError, "failed to remove repository")
+		return
+	}
+
+	username, _ := users.FromContext(r.Context())
+	slog.WithComponent("server").Info("Repository removed via API", map[string]interface{}{
+		"repo":  repo.Name,
+		"user":  username,
+		"trash": removal.TrashPath,
+	})
+	writeJSON(w, http.StatusOK, apiRepoRemoval{
+		Name:    repo.Name,
+		Trashed: removal.TrashPath != "",
+		Purged:  removal.Deleted,
+	})
+}
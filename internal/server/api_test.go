@@ -0,0 +1,252 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/tokens"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+)
+
+func TestSplitAPIRepoPath(t *testing.T) {
+	tests := []struct {
+		path  string
+		repo  string
+		parts []string
+	}{
+		{"app", "app", nil},
+		{"team/app.git", "team/app", nil},
+		{"team/app/branches", "team/app", []string{"branches"}},
+		{"app/tags", "app", []string{"tags"}},
+		{"team/web/app/commits", "team/web/app", []string{"commits"}},
+		{"app/commits/abc/status", "app", []string{"commits", "abc", "status"}},
+		{"team/app/tree/feature/x/src", "team/app", []string{"tree", "feature", "x", "src"}},
+	}
+	for _, tt := range tests {
+		repo, parts := splitAPIRepoPath(nil, tt.path)
+		if repo != tt.repo || !reflect.DeepEqual(parts, tt.parts) {
+			t.Errorf("splitAPIRepoPath(%q) = %q, %v; want %q, %v", tt.path, repo, parts, tt.repo, tt.parts)
+		}
+	}
+
+	// Registered names containing resource words are kept whole
+	reg := registry.NewWithPath(filepath.Join(t.TempDir(), "mappings.yaml"))
+	for _, name := range []string{"team/tree/x", "team/app", "team/tags"} {
+		if err := reg.Add(name, "", "/bare/"+name); err != nil {
+			t.Fatalf("Add() failed: %v", err)
+		}
+	}
+	tests = []struct {
+		path  string
+		repo  string
+		parts []string
+	}{
+		{"team/tree/x", "team/tree/x", nil},
+		{"team/tree/x/tree/main/src", "team/tree/x", []string{"tree", "main", "src"}},
+		{"team/tags", "team/tags", nil},
+		{"team/tags/tags", "team/tags", []string{"tags"}},
+		{"team/app/raw/main/tags", "team/app", []string{"raw", "main", "tags"}},
+		{"team/missing/tree/main", "team/missing", []string{"tree", "main"}},
+	}
+	for _, tt := range tests {
+		repo, parts := splitAPIRepoPath(reg, tt.path)
+		if repo != tt.repo || !reflect.DeepEqual(parts, tt.parts) {
+			t.Errorf("splitAPIRepoPath(reg, %q) = %q, %v; want %q, %v", tt.path, repo, parts, tt.repo, tt.parts)
+		}
+	}
+}
+
+func TestAPIRequiredScope(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   tokens.Scope
+	}{
+		{"GET", "/api/repos", tokens.ScopeRead},
+		{"GET", "/api/repos/app/commits", tokens.ScopeRead},
+		{"GET", "/api/repos/app/commits/abc/status", tokens.ScopeAdmin},
+		{"POST", "/api/repos/app/commits/abc/status", tokens.ScopeAdmin},
+		{"POST", "/api/repos", tokens.ScopeAdmin},
+		{"PATCH", "/api/repos/app", tokens.ScopeAdmin},
+		{"DELETE", "/api/repos/app", tokens.ScopeAdmin},
+	}
+	for _, tt := range tests {
+		if got := requiredScope(httptest.NewRequest(tt.method, tt.path, nil)); got != tt.want {
+			t.Errorf("requiredScope(%s %s) = %s, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCanManage(t *testing.T) {
+	a := &AuthMiddleware{}
+	asUser := func(name string) context.Context { return users.NewContext(context.Background(), name) }
+	owner := context.WithValue(context.Background(), ownerContextKey{}, true)
+
+	open := &registry.RepoMapping{Name: "app", CreatedBy: "alice"}
+	restricted := &registry.RepoMapping{Name: "api", CreatedBy: "alice", ACL: []registry.ACLEntry{
+		{User: "alice", Permission: registry.PermWrite},
+		{User: "bob", Permission: registry.PermAdmin},
+	}}
+	tests := []struct {
+		ctx  context.Context
+		repo *registry.RepoMapping
+		want bool
+	}{
+		{owner, restricted, true},
+		{asUser("alice"), open, true},
+		{asUser("bob"), open, false},
+		{asUser("alice"), restricted, false},
+		{asUser("bob"), restricted, true},
+		{context.Background(), open, false},
+	}
+	for i, tt := range tests {
+		r := httptest.NewRequest("DELETE", "/api/repos/"+tt.repo.Name, nil).WithContext(tt.ctx)
+		if got := a.canManage(r, tt.repo); got != tt.want {
+			t.Errorf("case %d: canManage(%s) = %v, want %v", i, tt.repo.Name, got, tt.want)
+		}
+	}
+
+	// Without authentication nobody manages anything
+	var disabled *AuthMiddleware
+	if disabled.canManage(httptest.NewRequest("DELETE", "/api/repos/api", nil), restricted) {
+		t.Error("canManage() should refuse everything when authentication is disabled")
+	}
+}
+
+func TestAPIRepoSourcePath(t *testing.T) {
+	s := &Server{cfg: &config.Config{}, auth: &AuthMiddleware{}}
+	repo := &registry.RepoMapping{Name: "app", SourcePath: "/home/owner/app", BarePath: t.TempDir()}
+
+	r := httptest.NewRequest("GET", "/api/repos/app", nil)
+	if got := s.newAPIRepo(r.WithContext(users.NewContext(r.Context(), "alice")), repo).SourcePath; got != "" {
+		t.Errorf("source path shown to a user: %q", got)
+	}
+	owner := context.WithValue(r.Context(), ownerContextKey{}, true)
+	if got := s.newAPIRepo(r.WithContext(owner), repo).SourcePath; got != repo.SourcePath {
+		t.Errorf("source path for the owner = %q, want %q", got, repo.SourcePath)
+	}
+}
+
+func TestAPIManageRequiresAuth(t *testing.T) {
+	s := &Server{cfg: &config.Config{}}
+	for _, req := range []struct{ method, path string }{
+		{"POST", "/api/repos"},
+		{"PATCH", "/api/repos/app"},
+		{"DELETE", "/api/repos/app?purge=true"},
+	} {
+		w := httptest.NewRecorder()
+		s.handleAPIRepos(w, httptest.NewRequest(req.method, req.path, strings.NewReader(`{"name":"app"}`)))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s %s without authentication = %d, want %d", req.method, req.path, w.Code, http.StatusForbidden)
+		}
+	}
+}
+
+func TestAPIPagination(t *testing.T) {
+	tests := []struct {
+		query         string
+		page, perPage int
+		ok            bool
+	}{
+		{"", 1, apiDefaultPerPage, true},
+		{"page=3&per_page=10", 3, 10, true},
+		{"page=0", 0, 0, false},
+		{"per_page=1000", 0, 0, false},
+		{"page=x", 0, 0, false},
+	}
+	for _, tt := range tests {
+		query, _ := url.ParseQuery(tt.query)
+		page, perPage, ok := apiPagination(query)
+		if page != tt.page || perPage != tt.perPage || ok != tt.ok {
+			t.Errorf("apiPagination(%q) = %d, %d, %v", tt.query, page, perPage, ok)
+		}
+	}
+}
+
+func TestRawContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		want string
+	}{
+		{"README.md", "# hello", "text/plain; charset=utf-8"},
+		{"index.html", "<html>", "text/plain; charset=utf-8"},
+		{"logo.svg", "<svg>", "text/plain; charset=utf-8"},
+		{"app.js", "alert(1)", "text/plain; charset=utf-8"},
+		{"Makefile", "all:\n", "text/plain; charset=utf-8"},
+		{"logo.png", "\x89PNG\r\n\x1a\n", "image/png"},
+		{"data.bin", "\x00\x01\x02", "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		if got := rawContentType(tt.name, []byte(tt.head)); got != tt.want {
+			t.Errorf("rawContentType(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompareRefsAndQueryPaths(t *testing.T) {
+	if base, head, ok := compareRefs([]string{"main...feature", "x"}); !ok || base != "main" || head != "feature/x" {
+		t.Errorf("compareRefs(main...feature/x) = %q, %q, %v", base, head, ok)
+	}
+	for _, bad := range []string{"main", "main...", "...main", "main...--output=x"} {
+		if _, _, ok := compareRefs([]string{bad}); ok {
+			t.Errorf("compareRefs(%q) should fail", bad)
+		}
+	}
+
+	paths, ok := queryPaths([]string{"src,docs/", "README.md"})
+	if !ok || !reflect.DeepEqual(paths, []string{"src", "docs", "README.md"}) {
+		t.Errorf("queryPaths = %v, %v", paths, ok)
+	}
+	if _, ok := queryPaths([]string{"src/../.."}); ok {
+		t.Error("queryPaths should refuse '..'")
+	}
+}
+
+func TestSplitArchiveName(t *testing.T) {
+	tests := []struct {
+		segments    []string
+		ref, suffix string
+		ok          bool
+	}{
+		{[]string{"main.tar.gz"}, "main", ".tar.gz", true},
+		{[]string{"feature", "x.zip"}, "feature/x", ".zip", true},
+		{[]string{"v1.0.tgz"}, "v1.0", ".tgz", true},
+		{[]string{"main.rar"}, "", "", false},
+		{[]string{"--output=x.zip"}, "", "", false},
+	}
+	for _, tt := range tests {
+		ref, suffix, ok := splitArchiveName(tt.segments)
+		if ref != tt.ref || suffix != tt.suffix || ok != tt.ok {
+			t.Errorf("splitArchiveName(%v) = %q, %q, %v", tt.segments, ref, suffix, ok)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+const (
+	// apiMaxBlobSize is the largest file /raw serves; clone for bigger ones
+	apiMaxBlobSize = 32 << 20
+	// apiTreeHistoryDepth bounds the commits searched for the last commit of
+	// tree entries; entries last changed further back have a null last_commit
+	apiTreeHistoryDepth = 1000
+)
+
+// apiTree is the /api/repos/{repo}/tree/{ref}/{path} response
+type apiTree struct {
+	Ref     string          `json:"ref"`
+	SHA     string          `json:"sha"`
+	Path    string          `json:"path"`
+	Entries []git.TreeEntry `json:"entries"`
+}
+
+// splitRefPath splits the segments after /tree/ or /raw/ into a ref and a
+// file path. Refs may contain slashes, so the shortest prefix naming a
+// commit wins: "feature/x/src/app.go" -> "feature/x", "src/app.go".
+// Without segments the ref is HEAD.
+func splitRefPath(barePath string, segments []string) (ref, sha, filePath string, err error) {
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsRune(segment, 0) {
+			return "", "", "", fmt.Errorf("invalid path")
+		}
+	}
+	if len(segments) == 0 {
+		sha, err = git.ResolveCommit(barePath, "HEAD")
+		return "HEAD", sha, "", err
+	}
+
+	for i := 1; i <= len(segments); i++ {
+		ref = strings.Join(segments[:i], "/")
+		if sha, err = git.ResolveCommit(barePath, ref); err == nil {
+			return ref, sha, strings.Join(segments[i:], "/"), nil
+		}
+	}
+	return "", "", "", git.ErrNotFound
+}
+
+// resolveAPIObject resolves the ref and path of a /tree or /raw request and
+// checks the object type. It writes the error response and returns false on failure.
+func resolveAPIObject(w http.ResponseWriter, repo *registry.RepoMapping, segments []string, wantType string) (ref, sha, filePath string, ok bool) {
+	ref, sha, filePath, err := splitRefPath(repo.BarePath, segments)
+	if errors.Is(err, git.ErrNotFound) {
+		writeAPIError(w, http.StatusNotFound, "ref not found")
+		return "", "", "", false
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return "", "", "", false
+	}
+
+	typ, err := git.ObjectType(repo.BarePath, sha, filePath)
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("path '%s' not found in '%s'", filePath, ref))
+		return "", "", "", false
+	}
+	if typ != wantType {
+		if wantType == "tree" {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("'%s' is not a directory, use /raw to read files", filePath))
+		} else {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("'%s' is not a file, use /tree to list directories", filePath))
+		}
+		return "", "", "", false
+	}
+	return ref, sha, filePath, true
+}
+
+// handleAPITree serves GET /api/repos/{repo}/tree/{ref}/{path}
+func (s *Server) handleAPITree(w http.ResponseWriter, r *http.Request, repo *registry.RepoMapping, segments []string) {
+	ref, sha, dir, ok := resolveAPIObject(w, repo, segments, "tree")
+	if !ok {
+		return
+	}
+
+	entries, err := git.ListTree(repo.BarePath, sha, dir)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to list tree")
+		return
+	}
+
+	// Walking history costs like a clone: share the git process limit
+	if s.gitLimiter != nil {
+		release, err := s.gitLimiter.Acquire(r.Context())
+		if err != nil {
+			writeAPIError(w, http.StatusServiceUnavailable, "server busy, try again later")
+			return
+		}
+		defer release()
+	}
+	lastCommits, err := git.LastCommits(r.Context(), repo.BarePath, sha, dir, entries, apiTreeHistoryDepth)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to read history")
+		return
+	}
+	for i := range entries {
+		if c, ok := lastCommits[entries[i].Path]; ok {
+			entries[i].LastCommit = &c
+		}
+	}
+
+	writeJSON(w, http.StatusOK, apiTree{Ref: ref, SHA: sha, Path: dir, Entries: entries})
+}
+
+// handleAPIRaw serves GET /api/repos/{repo}/raw/{ref}/{path}, streaming the
+// file straight from the bare repository
+func (s *Server) handleAPIRaw(w http.ResponseWriter, r *http.Request, repo *registry.RepoMapping, segments []string) {
+	_, sha, file, ok := resolveAPIObject(w, repo, segments, "blob")
+	if !ok {
+		return
+	}
+
+	size, err := git.BlobSize(repo.BarePath, sha, file)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to read file")
+		return
+	}
+	if size > apiMaxBlobSize {
+		writeAPIError(w, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("'%s' is %d bytes, larger than the %d bytes served by the API; clone the repository instead", file, size, apiMaxBlobSize))
+		return
+	}
+
+	blob, err := git.OpenBlob(repo.BarePath, sha, file)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to read file")
+		return
+	}
+	defer blob.Close()
+
+	// Peek does not consume: the sniffed bytes are still streamed below
+	reader := bufio.NewReaderSize(blob, 512)
+	head, _ := reader.Peek(512)
+
+	// SECURITY: Never let a browser render repository content as active content
+	w.Header().Set("Content-Type", rawContentType(file, head))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		_, _ = io.Copy(w, reader)
+	}
+}
+
+// rawContentType picks the content type of a raw file from its extension
+// or content. Text, including HTML, SVG and scripts, is served as plain text.
+func rawContentType(name string, head []byte) string {
+	ct := mime.TypeByExtension(path.Ext(name))
+	if ct == "" {
+		ct = http.DetectContentType(head)
+	}
+	mediaType, _, _ := mime.ParseMediaType(ct)
+	if strings.HasPrefix(mediaType, "text/") || strings.Contains(mediaType, "javascript") ||
+		strings.Contains(mediaType, "json") || strings.Contains(mediaType, "xml") {
+		return "text/plain; charset=utf-8"
+	}
+	if mediaType == "" {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
@@ -21,29 +21,52 @@
 package server
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/hex"
 	"fmt"
+	"math"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/JoeGlenn1213/lgh/internal/certs"
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/event"
+	"github.com/JoeGlenn1213/lgh/internal/lfs"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/slog"
+	"github.com/JoeGlenn1213/lgh/internal/tokens"
+	"github.com/JoeGlenn1213/lgh/internal/users"
 )
 
 // AuthConfig holds authentication configuration
 type AuthConfig struct {
 	Enabled      bool
 	Username     string
-	PasswordHash string // SHA256 hash with salt: "salt:hash"
+	PasswordHash string // argon2id hash (legacy "salt:hash" still accepted)
 }
 
 // AuthMiddleware provides HTTP Basic Authentication
 type AuthMiddleware struct {
 	username     string
-	passwordHash string // format: "salt:hash" or just "hash" for simple mode
+	passwordHash string // argon2id, or legacy "salt:hash" / "hash" (upgraded on login)
+	mu           sync.RWMutex
 	realm        string
+	users        *users.Store
+	tokens       *tokens.Store
+	registry     *registry.Registry
+	lockouts     *lockoutTracker
+	clientCerts  *certs.ClientStore // nil unless client certificates are accepted
+	// trustedProxies may name the client in X-Forwarded-For
+	trustedProxies []*net.IPNet
 }
 
 // NewAuthMiddleware creates a new authentication middleware
@@ -52,7 +75,31 @@ func NewAuthMiddleware(username, passwordHash string) *AuthMiddleware {
 		username:     username,
 		passwordHash: passwordHash,
 		realm:        "LGH Repository Access",
+		lockouts:     newLockoutTracker(),
+	}
+}
+
+// SetUserStore enables per-user credentials from the given user store.
+// The single account from config.yaml keeps working alongside it.
+func (a *AuthMiddleware) SetUserStore(store *users.Store) {
+	a.users = store
+}
+
+// SetTokenStore enables scoped access tokens from the given token store.
+// Tokens are accepted as a Bearer token or as the Basic auth password.
+func (a *AuthMiddleware) SetTokenStore(store *tokens.Store) {
+	a.tokens = store
+}
+
+// SetTrustedProxies sets the proxies whose X-Forwarded-For header is used
+// to find the client address for lockouts
+func (a *AuthMiddleware) SetTrustedProxies(entries []string) error {
+	nets, err := parseTrustedProxies(entries)
+	if err != nil {
+		return err
 	}
+	a.trustedProxies = nets
+	return nil
 }
 
 // Wrap wraps an http.Handler with authentication
@@ -64,43 +111,305 @@ func (a *AuthMiddleware) Wrap(next http.Handler) http.Handler {
 			return
 		}
 
-		user, pass, ok := r.BasicAuth()
-		if !ok {
-			a.unauthorized(w)
+		// SECURITY: Refuse locked-out clients before looking at credentials
+		ip := clientIP(r, a.trustedProxies)
+		if wait := a.lockouts.retryAfter(ipKey(ip)); wait > 0 {
+			a.tooManyRequests(w, wait)
 			return
 		}
 
-		// Constant-time comparison for username
-		usernameMatch := subtle.ConstantTimeCompare([]byte(user), []byte(a.username)) == 1
+		// owner is true for the config.yaml account and for tokens not bound
+		// to a user; both are managed by whoever runs the server.
+		var (
+			username string
+			owner    bool
+		)
+		if name, ok := a.clientCertIdentity(r); ok {
+			// Verified client certificate: the CommonName is the identity
+			username, owner = name, name == a.username
+		} else if raw, ok := bearerToken(r); ok {
+			// Bearer credentials can only be tokens
+			tok, err := a.verifyToken(raw)
+			if err != nil {
+				a.failed(w, r, ip, "")
+				return
+			}
+			if !a.checkTokenAccess(w, r, tok) {
+				return
+			}
+			if username, owner, ok = a.tokenAccount(tok); !ok {
+				a.failed(w, r, ip, "")
+				return
+			}
+		} else {
+			user, pass, ok := r.BasicAuth()
+			if !ok {
+				// No credentials yet: git retries with them after the 401
+				a.unauthorized(w)
+				return
+			}
 
-		// Check password against hash
-		passwordMatch := a.checkPassword(pass)
+			if wait := a.lockouts.retryAfter(userKey(user)); wait > 0 {
+				a.tooManyRequests(w, wait)
+				return
+			}
 
-		if !usernameMatch || !passwordMatch {
-			a.unauthorized(w)
+			if tok, err := a.verifyToken(pass); err == nil {
+				// Token used as the Basic auth password; the username is ignored
+				if !a.checkTokenAccess(w, r, tok) {
+					return
+				}
+				if username, owner, ok = a.tokenAccount(tok); !ok {
+					a.failed(w, r, ip, "")
+					return
+				}
+			} else if valid, isOwner := a.authenticate(user, pass); valid {
+				username, owner = user, isOwner
+				a.lockouts.succeed(userKey(user))
+			} else {
+				a.failed(w, r, ip, user)
+				return
+			}
+		}
+		a.lockouts.succeed(ipKey(ip))
+
+		// Per-repository access lists
+		if !owner && !a.authorizeRepo(w, r, username) {
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		// Pass the authenticated identity down the handler chain
+		ctx := users.NewContext(r.Context(), username)
+		if owner {
+			ctx = context.WithValue(ctx, ownerContextKey{}, true)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-// checkPassword verifies password against stored hash
-// Supports format: "salt:hash" or simple "hash"
-func (a *AuthMiddleware) checkPassword(password string) bool {
-	parts := strings.SplitN(a.passwordHash, ":", 2)
+// Lockouts returns the current failed-login records
+func (a *AuthMiddleware) Lockouts() []Lockout {
+	return a.lockouts.list()
+}
 
-	if len(parts) == 2 {
-		// Salted hash format: "salt:hash"
-		salt := parts[0]
-		expectedHash := parts[1]
-		actualHash := hashWithSalt(password, salt)
-		return subtle.ConstantTimeCompare([]byte(expectedHash), []byte(actualHash)) == 1
+// ClearLockouts removes the record for key ("ip:<addr>" or "user:<name>"),
+// or all records if key is empty. It returns the number of removed records.
+func (a *AuthMiddleware) ClearLockouts(key string) int {
+	return a.lockouts.clear(key)
+}
+
+// failed records a failed login for the client IP and username,
+// publishes an auth.failed event and sends the response.
+func (a *AuthMiddleware) failed(w http.ResponseWriter, r *http.Request, ip, username string) {
+	rec := a.lockouts.fail(ipKey(ip))
+	if username != "" {
+		if userRec := a.lockouts.fail(userKey(username)); userRec.LockedUntil.After(rec.LockedUntil) {
+			rec = userRec
+		}
 	}
 
-	// Simple hash format (not recommended but supported)
-	simpleHash := sha256Hex(password)
-	return subtle.ConstantTimeCompare([]byte(a.passwordHash), []byte(simpleHash)) == 1
+	payload := map[string]interface{}{
+		"ip":       ip,
+		"failures": rec.Failures,
+		"path":     r.URL.Path,
+	}
+	if username != "" {
+		payload["user"] = username
+	}
+
+	now := time.Now()
+	if rec.Locked(now) {
+		payload["locked_until"] = rec.LockedUntil.Format(time.RFC3339)
+		slog.Warn("Authentication lockout", map[string]interface{}{"key": rec.Key, "failures": rec.Failures})
+	}
+	event.Publish(event.AuthFailed, a.requestRepo(r), payload)
+
+	if rec.Locked(now) {
+		a.tooManyRequests(w, rec.LockedUntil.Sub(now))
+		return
+	}
+	a.unauthorized(w)
+}
+
+// tooManyRequests sends a 429 response with a Retry-After header
+func (a *AuthMiddleware) tooManyRequests(w http.ResponseWriter, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	http.Error(w, fmt.Sprintf("Too many failed login attempts. Try again in %d seconds.", seconds), http.StatusTooManyRequests)
+}
+
+// authenticate checks credentials against the config account and the user store.
+// owner is true when the config account matched.
+func (a *AuthMiddleware) authenticate(username, password string) (valid, owner bool) {
+	if a.username != "" {
+		// Constant-time comparison for username
+		usernameMatch := subtle.ConstantTimeCompare([]byte(username), []byte(a.username)) == 1
+
+		// Check password against hash
+		passwordMatch := a.checkPassword(password)
+
+		if usernameMatch && passwordMatch {
+			a.upgradeConfigHash(password)
+			return true, true
+		}
+	}
+
+	if a.users == nil {
+		return false, false
+	}
+
+	u, err := a.users.Find(username)
+	if err != nil {
+		return false, false
+	}
+	if !ValidatePasswordHash(password, u.PasswordHash) {
+		return false, false
+	}
+
+	// Transparently migrate legacy hashes now that we know the password
+	if IsLegacyHash(u.PasswordHash) {
+		if hash, err := HashPassword(password); err == nil {
+			if err := a.users.SetPassword(u.Name, hash); err != nil {
+				slog.Warn("Failed to upgrade password hash", map[string]interface{}{"user": u.Name, "error": err.Error()})
+			} else {
+				slog.Info("Upgraded legacy password hash", map[string]interface{}{"user": u.Name})
+			}
+		}
+	}
+	return true, false
+}
+
+// upgradeConfigHash replaces a legacy config.yaml password hash with argon2id
+// after a successful login with the given password.
+func (a *AuthMiddleware) upgradeConfigHash(password string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !IsLegacyHash(a.passwordHash) {
+		return
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return
+	}
+	if err := config.SaveAuthPasswordHash(hash); err != nil {
+		slog.Warn("Failed to upgrade password hash", map[string]interface{}{"user": a.username, "error": err.Error()})
+		return
+	}
+	a.passwordHash = hash
+	slog.Info("Upgraded legacy password hash", map[string]interface{}{"user": a.username})
+}
+
+// verifyToken checks raw against the token store
+func (a *AuthMiddleware) verifyToken(raw string) (*tokens.Token, error) {
+	if a.tokens == nil {
+		return nil, fmt.Errorf("tokens are not enabled")
+	}
+	return a.tokens.Verify(raw)
+}
+
+// checkTokenAccess verifies the token's scope and repo restriction for the request.
+// It writes a 403 response and returns false if access is denied.
+func (a *AuthMiddleware) checkTokenAccess(w http.ResponseWriter, r *http.Request, tok *tokens.Token) bool {
+	required := requiredScope(r)
+	if !tok.Scope.Allows(required) {
+		http.Error(w, fmt.Sprintf("Forbidden: token scope '%s' does not allow %s access", tok.Scope, required), http.StatusForbidden)
+		return false
+	}
+
+	if tok.Repo != "" {
+		repo := a.requestRepo(r)
+		if newName, ok := a.resolveRedirect(repo); ok && !tok.AllowsRepo(repo) {
+			repo = newName
+		}
+		if repo == "" || !tok.AllowsRepo(repo) {
+			http.Error(w, fmt.Sprintf("Forbidden: token is restricted to repository '%s'", tok.Repo), http.StatusForbidden)
+			return false
+		}
+	}
+
+	return true
+}
+
+// tokenIdentity returns the name a token authenticates as
+func tokenIdentity(tok *tokens.Token) string {
+	if tok.User != "" {
+		return tok.User
+	}
+	return "token-" + tok.ID
+}
+
+// tokenAccount returns the identity of a token and whether it acts as the
+// owner. Tokens bound to a user that no longer exists are refused, like the
+// SSH keys of removed users.
+func (a *AuthMiddleware) tokenAccount(tok *tokens.Token) (username string, owner, ok bool) {
+	if tok.User == "" || tok.User == a.username {
+		return tokenIdentity(tok), true, true
+	}
+	if !a.userExists(tok.User) {
+		return "", false, false
+	}
+	return tok.User, false, true
+}
+
+// userExists reports whether name has an account in users.yaml
+func (a *AuthMiddleware) userExists(name string) bool {
+	if a.users == nil {
+		return false
+	}
+	_, err := a.users.Find(name)
+	return err == nil
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer" header
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	scheme, value, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	value = strings.TrimSpace(value)
+	return value, value != ""
+}
+
+// requiredScope maps a request to the token scope it needs: the commit
+// status API and other /api/... writes need admin, git-receive-pack and LFS
+// uploads need write, everything else (including API reads) read.
+func requiredScope(r *http.Request) tokens.Scope {
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		if isAPIRead(r) {
+			return tokens.ScopeRead
+		}
+		return tokens.ScopeAdmin
+	}
+	if strings.HasSuffix(r.URL.Path, "/git-receive-pack") || r.URL.Query().Get("service") == "git-receive-pack" || lfs.IsWriteRequest(r) {
+		return tokens.ScopeWrite
+	}
+	return tokens.ScopeRead
+}
+
+// requestRepo extracts the repository name (without .git) from a git or API request path
+func (a *AuthMiddleware) requestRepo(r *http.Request) string {
+	path := r.URL.Path
+	if rest, ok := strings.CutPrefix(path, "/api/repos/"); ok {
+		repo, _ := splitAPIRepoPath(a.registry, rest)
+		return repo
+	}
+
+	name, _, _ := registry.NameFromPath(path)
+	return name
+}
+
+// checkPassword verifies password against the config account hash
+func (a *AuthMiddleware) checkPassword(password string) bool {
+	a.mu.RLock()
+	hash := a.passwordHash
+	a.mu.RUnlock()
+	return ValidatePasswordHash(password, hash)
 }
 
 // unauthorized sends a 401 response
@@ -109,18 +418,13 @@ func (a *AuthMiddleware) unauthorized(w http.ResponseWriter) {
 	http.Error(w, "Unauthorized", http.StatusUnauthorized)
 }
 
-// HashPassword creates a salted SHA256 hash of a password
-// Returns format: "salt:hash"
+// HashPassword creates an argon2id hash of a password
+// Returns format: "$argon2id$v=19$m=...,t=...,p=...$salt$hash"
 func HashPassword(password string) (string, error) {
-	salt, err := generateSalt(16)
-	if err != nil {
-		return "", err
-	}
-	hash := hashWithSalt(password, salt)
-	return fmt.Sprintf("%s:%s", salt, hash), nil
+	return hashArgon2id(password)
 }
 
-// hashWithSalt creates HMAC-SHA256 hash with salt
+// hashWithSalt creates HMAC-SHA256 hash with salt, to verify legacy "salt:hash" passwords
 func hashWithSalt(password, salt string) string {
 	h := hmac.New(sha256.New, []byte(salt))
 	h.Write([]byte(password))
@@ -133,15 +437,6 @@ func sha256Hex(s string) string {
 	return hex.EncodeToString(h[:])
 }
 
-// generateSalt generates a random salt
-func generateSalt(length int) (string, error) {
-	bytes := make([]byte, length)
-	if _, err := rand.Read(bytes); err != nil {
-		return "", err
-	}
-	return hex.EncodeToString(bytes), nil
-}
-
 // GenerateToken generates a secure random token
 func GenerateToken(length int) (string, error) {
 	bytes := make([]byte, length)
@@ -169,7 +464,12 @@ func LoadAuthFromEnv() *AuthConfig {
 }
 
 // ValidatePasswordHash checks if a password matches the hash
+// Supports argon2id ("$argon2id$..."), legacy salted ("salt:hash") and legacy unsalted SHA256
 func ValidatePasswordHash(password, hash string) bool {
+	if strings.HasPrefix(hash, argon2Prefix) {
+		return verifyArgon2id(password, hash)
+	}
+
 	parts := strings.SplitN(hash, ":", 2)
 	if len(parts) == 2 {
 		salt := parts[0]
@@ -0,0 +1,128 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JoeGlenn1213/lgh/internal/certs"
+	"github.com/JoeGlenn1213/lgh/internal/tokens"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+)
+
+// newTestAuth returns a middleware with a user store holding alice and a
+// token bound to her
+func newTestAuth(t *testing.T) (*AuthMiddleware, *users.Store, string) {
+	t.Helper()
+	dir := t.TempDir()
+	userStore := users.NewStoreWithPath(filepath.Join(dir, "users.yaml"))
+	if err := userStore.Add("alice", "unused"); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	tokenStore := tokens.NewStoreWithPath(filepath.Join(dir, "tokens.yaml"))
+	err := tokenStore.Add(tokens.Token{
+		ID:         "t1",
+		User:       "alice",
+		Scope:      tokens.ScopeRead,
+		SecretHash: tokens.HashSecret("secret"),
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	a := NewAuthMiddleware("owner", "")
+	a.SetUserStore(userStore)
+	a.SetTokenStore(tokenStore)
+	return a, userStore, tokens.Format("t1", "secret")
+}
+
+func TestTokenOfRemovedUser(t *testing.T) {
+	a, userStore, raw := newTestAuth(t)
+	handler := a.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if name, _ := users.FromContext(r.Context()); name != "alice" {
+			t.Errorf("identity = %q, want alice", name)
+		}
+	}))
+
+	get := func() int {
+		r := httptest.NewRequest("GET", "/api/repos", nil)
+		r.Header.Set("Authorization", "Bearer "+raw)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if code := get(); code != http.StatusOK {
+		t.Fatalf("token of alice = %d, want %d", code, http.StatusOK)
+	}
+	if err := userStore.Remove("alice"); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+	if code := get(); code != http.StatusUnauthorized {
+		t.Errorf("token of removed alice = %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+func TestClientCertOfRemovedUser(t *testing.T) {
+	a, userStore, _ := newTestAuth(t)
+	dir := t.TempDir()
+	ca, _, err := certs.InitCA(dir)
+	if err != nil {
+		t.Fatalf("InitCA() failed: %v", err)
+	}
+	clientStore := certs.NewClientStore(dir)
+	a.SetClientCertStore(clientStore)
+
+	request := func(name string) *http.Request {
+		certPath, keyPath := certs.ClientCertPaths(dir, name)
+		cert, err := ca.IssueClientCert(certPath, keyPath, name, time.Hour)
+		if err != nil {
+			t.Fatalf("IssueClientCert() failed: %v", err)
+		}
+		if err := clientStore.Add(name, cert); err != nil {
+			t.Fatalf("Add() failed: %v", err)
+		}
+		r := httptest.NewRequest("GET", "/api/repos", nil)
+		r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		return r
+	}
+
+	if name, ok := a.clientCertIdentity(request("owner")); !ok || name != "owner" {
+		t.Errorf("certificate of the config.yaml account = %q, %v", name, ok)
+	}
+	alice := request("alice")
+	if name, ok := a.clientCertIdentity(alice); !ok || name != "alice" {
+		t.Errorf("certificate of alice = %q, %v", name, ok)
+	}
+	if err := userStore.Remove("alice"); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+	if _, ok := a.clientCertIdentity(alice); ok {
+		t.Error("certificate of removed alice should be refused")
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/JoeGlenn1213/lgh/internal/certs"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+)
+
+// SetClientCertStore enables client certificate authentication. Only
+// certificates recorded in store and not revoked are accepted.
+func (a *AuthMiddleware) SetClientCertStore(store *certs.ClientStore) {
+	a.clientCerts = store
+}
+
+// clientCertIdentity returns the CommonName of a verified client certificate.
+// The TLS layer has already checked the chain against the local CA. A
+// CommonName equal to the config.yaml account authenticates as the owner;
+// any other must name an existing user, so certificates of removed users
+// are refused like their SSH keys.
+func (a *AuthMiddleware) clientCertIdentity(r *http.Request) (string, bool) {
+	if a.clientCerts == nil || r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return "", false
+	}
+
+	leaf := r.TLS.VerifiedChains[0][0]
+	name := leaf.Subject.CommonName
+	if users.ValidateName(name) != nil || !a.clientCerts.Allowed(leaf) {
+		return "", false
+	}
+	if name != a.username && !a.userExists(name) {
+		return "", false
+	}
+	return name, true
+}
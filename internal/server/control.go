@@ -0,0 +1,158 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+// controlSocketName is the Unix socket used by local CLI commands to query
+// and manage the running server. Unlike /debug/* it cannot be reached through
+// a tunnel, since it never listens on TCP.
+const controlSocketName = "lgh-control.sock"
+
+// GetControlSocketPath returns the control socket path
+func GetControlSocketPath() string {
+	return filepath.Join(config.Get().DataDir, controlSocketName)
+}
+
+// startControl starts the HTTP-over-Unix-socket control listener
+func (s *Server) startControl() {
+	sockPath := GetControlSocketPath()
+
+	// Cleanup old socket
+	if _, err := os.Stat(sockPath); err == nil {
+		if err := os.Remove(sockPath); err != nil {
+			ui.Error("Failed to remove old control socket: %v", err)
+			return
+		}
+	}
+
+	listener, err := net.Listen("unix", sockPath)
+	if err != nil {
+		ui.Error("Failed to start control listener: %v", err)
+		return
+	}
+
+	// Restrict permissions so only the current user can access
+	if err := os.Chmod(sockPath, 0600); err != nil {
+		ui.Warning("Failed to set socket permissions: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/lockouts", s.handleControlLockouts)
+	mux.HandleFunc("/git-queue", s.handleControlGitQueue)
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		defer os.Remove(sockPath)
+		_ = srv.Serve(listener)
+	}()
+}
+
+// handleControlLockouts lists (GET) or clears (DELETE ?key=) auth lockouts
+func (s *Server) handleControlLockouts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if s.auth == nil {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"auth_enabled": false, "lockouts": []Lockout{}})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"auth_enabled": true, "lockouts": s.auth.Lockouts()})
+	case http.MethodDelete:
+		n := s.auth.ClearLockouts(r.URL.Query().Get("key"))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"cleared": n})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GitQueueStatus is the /git-queue control response
+type GitQueueStatus struct {
+	Limited bool `json:"limited"`
+	git.LimiterStats
+}
+
+// handleControlGitQueue reports running and queued git processes
+func (s *Server) handleControlGitQueue(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	status := GitQueueStatus{Limited: s.gitLimiter != nil}
+	if s.gitLimiter != nil {
+		status.LimiterStats = s.gitLimiter.Stats()
+	}
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+// ControlRequest sends a request to the running server's control socket
+// and decodes the JSON response into out (if non-nil).
+func ControlRequest(method, path string, out interface{}) error {
+	sockPath := GetControlSocketPath()
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", sockPath)
+			},
+		},
+	}
+
+	req, err := http.NewRequest(method, "http://lgh"+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach server control socket (is the server running?): %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("server returned %s: %s", resp.Status, string(body))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
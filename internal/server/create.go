@@ -0,0 +1,99 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/repos"
+	"github.com/JoeGlenn1213/lgh/internal/slog"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+// createOnPush creates and registers the bare repository name for a push by
+// username, if it does not exist yet and a push-to-create rule allows it.
+// It reports whether a repository was created.
+func createOnPush(cfg *config.Config, reg *registry.Registry, userStore *users.Store, username, name string) (bool, error) {
+	if cfg.ReadOnly {
+		return false, nil
+	}
+
+	var groups []string
+	if userStore != nil && username != "" {
+		if u, err := userStore.Find(username); err == nil {
+			groups = u.Groups
+		}
+	}
+	allowed, err := reg.CanCreate(name, username, groups)
+	if err != nil || !allowed {
+		return false, err
+	}
+
+	_, err = repos.Create(reg, cfg, repos.CreateOptions{
+		Name:      name,
+		CreatedBy: username,
+		URL:       cfg.BaseURL(cfg.BindAddress) + registry.URLPath(name),
+		Via:       "push",
+	})
+	if errors.Is(err, repos.ErrExists) {
+		// Created by a concurrent push, or registered with a missing bare repository
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	ui.Success("Created repository '%s' on push by %s", name, username)
+	slog.WithComponent("server").Info("Repository created on push", map[string]interface{}{
+		"repo": name,
+		"user": username,
+	})
+	return true, nil
+}
+
+// pushToCreateMiddleware creates a missing repository when an authorized user
+// starts pushing to it. git asks for the receive-pack ref advertisement
+// first, so the repository exists before the pack (and any LFS upload) is sent.
+// Anonymous requests never create repositories, even with a "*" rule.
+func (s *Server) pushToCreateMiddleware(reg *registry.Registry, userStore *users.Store, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		isReceivePack := r.URL.Query().Get("service") == "git-receive-pack" || strings.HasSuffix(r.URL.Path, "/git-receive-pack")
+		if isReceivePack {
+			if name, _, ok := registry.NameFromPath(r.URL.Path); ok && !git.IsBareRepo(registry.BarePathFor(s.cfg.ReposDir, name)) {
+				username, _ := users.FromContext(r.Context())
+				if username == "" {
+					next.ServeHTTP(w, r)
+					return
+				}
+				if _, err := createOnPush(s.cfg, reg, userStore, username, name); err != nil {
+					ui.Warning("Failed to create repository '%s' on push: %v", name, err)
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+)
+
+func TestPushToCreateRequiresUser(t *testing.T) {
+	dir := t.TempDir()
+	reg := registry.NewWithPath(filepath.Join(dir, "mappings.yaml"))
+	if err := reg.AllowCreate(registry.CreateRule{Namespace: registry.AnyNamespace, User: registry.AnyUser}); err != nil {
+		t.Fatalf("AllowCreate() failed: %v", err)
+	}
+	s := &Server{cfg: &config.Config{DataDir: dir, ReposDir: filepath.Join(dir, "repos")}}
+	handler := s.pushToCreateMiddleware(reg, nil, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	barePath := registry.BarePathFor(s.cfg.ReposDir, "app")
+
+	r := httptest.NewRequest(http.MethodGet, "/app.git/info/refs?service=git-receive-pack", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	if git.IsBareRepo(barePath) || reg.Exists("app") {
+		t.Fatal("anonymous request created a repository")
+	}
+
+	r = r.WithContext(users.NewContext(r.Context(), "alice"))
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	if !git.IsBareRepo(barePath) {
+		t.Skip("git not available")
+	}
+	if !reg.Exists("app") {
+		t.Error("authenticated request did not register the repository")
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+// startGitDaemon starts the git:// listener next to the HTTP server. Only
+// repositories with a git-daemon-export-ok marker and no access list are
+// served, without auth.
+func (s *Server) startGitDaemon() error {
+	if s.cfg.GitDaemonPort == s.cfg.Port {
+		return fmt.Errorf("git_daemon_port %d is the HTTP port; choose another port", s.cfg.GitDaemonPort)
+	}
+
+	daemon, err := git.NewDaemon(s.cfg.ReposDir)
+	if err != nil {
+		return err
+	}
+	daemon.SetLimiter(s.gitLimiter)
+	daemon.OnRequest = logDaemonRequest
+	// git:// clients are anonymous, so access lists cannot be checked
+	reg := registry.New()
+	daemon.Hidden = func(name string) bool { return hiddenFromDaemon(reg, name) }
+
+	addr := net.JoinHostPort(s.cfg.BindAddress, fmt.Sprintf("%d", s.cfg.GitDaemonPort))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	s.gitDaemon = daemon
+	go func() {
+		if err := daemon.Serve(ln); err != nil {
+			ui.Error("git:// listener stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// hiddenFromDaemon reports whether the repository name must not be served
+// over git:// because it has an access list
+func hiddenFromDaemon(reg *registry.Registry, name string) bool {
+	repo, err := reg.Find(name)
+	if errors.Is(err, registry.ErrNotFound) {
+		return false
+	}
+	// SECURITY: Hide the repository if its access list cannot be read
+	return err != nil || repo.Restricted()
+}
+
+// logDaemonRequest prints git:// requests like the HTTP request log
+func logDaemonRequest(service, path, remote string, err error, d time.Duration) {
+	status := ui.Green("ok")
+	if err != nil {
+		status = ui.Red(err.Error())
+	}
+	fmt.Printf("%s %s %s %s %s\n", ui.Gray("GIT"), path, status, ui.Gray(d.String()), ui.Gray(remote))
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+func TestHiddenFromDaemon(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mappings.yaml")
+	reg := registry.NewWithPath(path)
+	if err := reg.Add("app", "", filepath.Join(t.TempDir(), "app.git")); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	if hiddenFromDaemon(reg, "app") || hiddenFromDaemon(reg, "unregistered") {
+		t.Error("repositories without an access list should be served")
+	}
+
+	if err := os.WriteFile(path, []byte("repos: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if !hiddenFromDaemon(reg, "app") {
+		t.Error("an unreadable registry must hide the repository")
+	}
+}
@@ -25,16 +25,18 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/JoeGlenn1213/lgh/internal/config"
 	"github.com/JoeGlenn1213/lgh/internal/event"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
 	"github.com/JoeGlenn1213/lgh/pkg/ui"
 )
 
 // startIPC starts the Unix Domain Socket listener for event subscription
 func (s *Server) startIPC() {
-	cfg := config.Get()
-	sockPath := filepath.Join(cfg.DataDir, "lgh.sock")
+	sockPath := GetIPCSocketPath()
 
 	// Cleanup old socket
 	if _, err := os.Stat(sockPath); err == nil {
@@ -79,12 +81,96 @@ func handleIPCConnection(conn net.Conn) {
 	ch := event.SubscribeClient()
 	defer event.UnsubscribeClient(ch)
 
-	// 2. Start Writer (Server -> Client)
+	// 2. Start Reader (Client -> Server): managed hooks report to the server.
+	// Subscribers never write, so their reader just waits for disconnect.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		decoder := json.NewDecoder(conn)
+		for {
+			var msg IPCMessage
+			if err := decoder.Decode(&msg); err != nil {
+				return
+			}
+			handleIPCMessage(msg)
+		}
+	}()
+
+	// 3. Start Writer (Server -> Client)
 	// We run this in the main goroutine to keep the handler alive until disconnect
 	encoder := json.NewEncoder(conn)
-	for evt := range ch {
-		if err := encoder.Encode(evt); err != nil {
-			return // Client disconnected or error
+	for {
+		select {
+		case evt, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := encoder.Encode(evt); err != nil {
+				return // Client disconnected or error
+			}
+		case <-done:
+			return
 		}
 	}
 }
+
+// IPCMessage is sent to the server over the IPC socket
+type IPCMessage struct {
+	Kind    string                 `json:"kind"`
+	Type    event.Type             `json:"type,omitempty"`
+	Repo    string                 `json:"repo,omitempty"` // repository name, e.g. "team/api"
+	Payload map[string]interface{} `json:"payload,omitempty"`
+	// Post-receive reports
+	PushID  string          `json:"push_id,omitempty"`
+	Pusher  string          `json:"pusher,omitempty"`
+	Updates []git.RefUpdate `json:"updates,omitempty"`
+}
+
+const (
+	// IPCKindPublish asks the server to publish an event on its bus
+	IPCKindPublish = "publish"
+	// IPCKindPostReceive reports the ref updates of a completed push
+	IPCKindPostReceive = "post-receive"
+)
+
+// ipcPublishable lists the events clients may publish through the server
+var ipcPublishable = map[event.Type]bool{
+	event.GitPushRejected: true,
+}
+
+// handleIPCMessage processes one message received from a client
+func handleIPCMessage(msg IPCMessage) {
+	switch msg.Kind {
+	case IPCKindPublish:
+		if ipcPublishable[msg.Type] {
+			event.Publish(msg.Type, msg.Repo, msg.Payload)
+		}
+	case IPCKindPostReceive:
+		reposDir := config.Get().ReposDir
+		barePath := registry.BarePathFor(reposDir, msg.Repo)
+		// SECURITY: Only repositories inside the repos directory
+		if registry.ValidateName(msg.Repo) != nil || !registry.IsPathSafe(reposDir, barePath) || !git.IsBareRepo(barePath) {
+			return
+		}
+		git.PublishRefUpdates(barePath, msg.Repo, msg.Pusher, msg.PushID, msg.Updates)
+		go maintainRepo(barePath)
+	}
+}
+
+// GetIPCSocketPath returns the IPC socket path
+func GetIPCSocketPath() string {
+	return filepath.Join(config.Get().DataDir, "lgh.sock")
+}
+
+// SendIPCMessage delivers a message to the running server.
+// It fails if the server is not running.
+func SendIPCMessage(msg IPCMessage) error {
+	conn, err := net.DialTimeout("unix", GetIPCSocketPath(), 2*time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_ = conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	return json.NewEncoder(conn).Encode(msg)
+}
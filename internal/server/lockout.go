@@ -0,0 +1,226 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Brute-force protection defaults
+const (
+	// lockoutThreshold is the number of failures allowed before the first lockout
+	lockoutThreshold = 5
+	// lockoutBase is the first lockout duration; it doubles with every further failure
+	lockoutBase = 30 * time.Second
+	// lockoutMax caps the lockout duration
+	lockoutMax = 15 * time.Minute
+	// lockoutForget resets a record after this long without failures
+	lockoutForget = time.Hour
+)
+
+// Lockout describes the failed-attempt state of a client IP or username
+type Lockout struct {
+	Key         string    `json:"key"` // "ip:<addr>" or "user:<name>"
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"last_failure"`
+	LockedUntil time.Time `json:"locked_until,omitempty"`
+}
+
+// Locked reports whether the lockout is still active at now
+func (l Lockout) Locked(now time.Time) bool {
+	return now.Before(l.LockedUntil)
+}
+
+// lockoutTracker counts failed logins per key and computes lockouts
+type lockoutTracker struct {
+	mu      sync.Mutex
+	records map[string]*Lockout
+	now     func() time.Time
+}
+
+// newLockoutTracker creates an empty tracker
+func newLockoutTracker() *lockoutTracker {
+	return &lockoutTracker{
+		records: make(map[string]*Lockout),
+		now:     time.Now,
+	}
+}
+
+// retryAfter returns how long key is still locked out, or 0
+func (t *lockoutTracker) retryAfter(key string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rec, ok := t.records[key]
+	if !ok {
+		return 0
+	}
+	if wait := rec.LockedUntil.Sub(t.now()); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// fail records a failed attempt for key and returns the updated record
+func (t *lockoutTracker) fail(key string) Lockout {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.pruneLocked(now)
+
+	rec, ok := t.records[key]
+	if !ok {
+		rec = &Lockout{Key: key}
+		t.records[key] = rec
+	}
+
+	rec.Failures++
+	rec.LastFailure = now
+
+	// Exponential backoff: 30s, 1m, 2m, 4m, ... up to lockoutMax
+	if over := rec.Failures - lockoutThreshold; over >= 0 {
+		d := time.Duration(float64(lockoutBase) * math.Pow(2, float64(over)))
+		if d <= 0 || d > lockoutMax {
+			d = lockoutMax
+		}
+		rec.LockedUntil = now.Add(d)
+	}
+
+	return *rec
+}
+
+// succeed clears the record for key after a successful login
+func (t *lockoutTracker) succeed(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.records, key)
+}
+
+// list returns a snapshot of all records, most recent failure first
+func (t *lockoutTracker) list() []Lockout {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pruneLocked(t.now())
+
+	out := make([]Lockout, 0, len(t.records))
+	for _, rec := range t.records {
+		out = append(out, *rec)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].LastFailure.After(out[j].LastFailure)
+	})
+	return out
+}
+
+// clear removes the record for key, or all records if key is empty.
+// It returns the number of removed records.
+func (t *lockoutTracker) clear(key string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if key == "" {
+		n := len(t.records)
+		t.records = make(map[string]*Lockout)
+		return n
+	}
+	if _, ok := t.records[key]; ok {
+		delete(t.records, key)
+		return 1
+	}
+	return 0
+}
+
+// pruneLocked drops records that are neither locked nor recently failed.
+// Caller must hold t.mu.
+func (t *lockoutTracker) pruneLocked(now time.Time) {
+	for key, rec := range t.records {
+		if !rec.Locked(now) && now.Sub(rec.LastFailure) > lockoutForget {
+			delete(t.records, key)
+		}
+	}
+}
+
+// ipKey and userKey build tracker keys
+func ipKey(ip string) string     { return "ip:" + ip }
+func userKey(name string) string { return "user:" + name }
+
+// parseTrustedProxies parses trusted proxy IPs and CIDRs
+func parseTrustedProxies(entries []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy '%s'", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy '%s'", entry)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// clientIP returns the address of the client making the request. The last
+// X-Forwarded-For hop is used only for requests from a trusted proxy; any
+// other peer, loopback included (ssh -R), could forge the header.
+func clientIP(r *http.Request, trusted []*net.IPNet) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return host
+	}
+	for _, proxy := range trusted {
+		if !proxy.Contains(ip) {
+			continue
+		}
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			hops := strings.Split(xff, ",")
+			if last := strings.TrimSpace(hops[len(hops)-1]); net.ParseIP(last) != nil {
+				return last
+			}
+		}
+		break
+	}
+
+	return host
+}
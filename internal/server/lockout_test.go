@@ -0,0 +1,157 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestTracker(now *time.Time) *lockoutTracker {
+	t := newLockoutTracker()
+	t.now = func() time.Time { return *now }
+	return t
+}
+
+func TestLockoutThresholdAndBackoff(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tr := newTestTracker(&now)
+	key := ipKey("10.0.0.5")
+
+	for i := 1; i < lockoutThreshold; i++ {
+		tr.fail(key)
+		if wait := tr.retryAfter(key); wait != 0 {
+			t.Fatalf("locked after %d failures, want unlocked below threshold", i)
+		}
+	}
+
+	rec := tr.fail(key)
+	if wait := tr.retryAfter(key); wait != lockoutBase {
+		t.Errorf("first lockout = %v, want %v", wait, lockoutBase)
+	}
+	if !rec.Locked(now) {
+		t.Error("record should be locked")
+	}
+
+	// Each further failure doubles the lockout
+	now = now.Add(lockoutBase)
+	tr.fail(key)
+	if wait := tr.retryAfter(key); wait != 2*lockoutBase {
+		t.Errorf("second lockout = %v, want %v", wait, 2*lockoutBase)
+	}
+
+	// ... up to the cap
+	for i := 0; i < 20; i++ {
+		tr.fail(key)
+	}
+	if wait := tr.retryAfter(key); wait != lockoutMax {
+		t.Errorf("capped lockout = %v, want %v", wait, lockoutMax)
+	}
+}
+
+func TestLockoutSucceedAndClear(t *testing.T) {
+	now := time.Now()
+	tr := newTestTracker(&now)
+
+	for i := 0; i < lockoutThreshold; i++ {
+		tr.fail(userKey("bob"))
+	}
+	tr.fail(ipKey("10.0.0.1"))
+
+	tr.succeed(userKey("bob"))
+	if wait := tr.retryAfter(userKey("bob")); wait != 0 {
+		t.Error("succeed() should clear the lockout")
+	}
+
+	if n := tr.clear(""); n != 1 {
+		t.Errorf("clear() removed %d records, want 1", n)
+	}
+	if len(tr.list()) != 0 {
+		t.Error("list() should be empty after clear")
+	}
+}
+
+func TestLockoutForget(t *testing.T) {
+	now := time.Now()
+	tr := newTestTracker(&now)
+
+	tr.fail(ipKey("10.0.0.1"))
+	now = now.Add(lockoutForget + time.Minute)
+	if len(tr.list()) != 0 {
+		t.Error("stale records should be pruned")
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted, err := parseTrustedProxies([]string{"127.0.0.1", "10.1.0.0/16"})
+	if err != nil {
+		t.Fatalf("parseTrustedProxies() failed: %v", err)
+	}
+
+	r := httptest.NewRequest("GET", "/repo.git/info/refs", nil)
+	r.RemoteAddr = "192.168.1.20:5555"
+	r.Header.Set("X-Forwarded-For", "1.2.3.4")
+	if ip := clientIP(r, trusted); ip != "192.168.1.20" {
+		t.Errorf("clientIP() = %q, forwarded header must be ignored for untrusted peers", ip)
+	}
+
+	r.RemoteAddr = "127.0.0.1:5555"
+	r.Header.Set("X-Forwarded-For", "9.9.9.9, 203.0.113.7")
+	if ip := clientIP(r, trusted); ip != "203.0.113.7" {
+		t.Errorf("clientIP() = %q, want last forwarded hop", ip)
+	}
+	if ip := clientIP(r, nil); ip != "127.0.0.1" {
+		t.Errorf("clientIP() = %q, loopback is not trusted unless configured", ip)
+	}
+
+	r.Header.Del("X-Forwarded-For")
+	if ip := clientIP(r, trusted); ip != "127.0.0.1" {
+		t.Errorf("clientIP() = %q, want 127.0.0.1", ip)
+	}
+
+	if _, err := parseTrustedProxies([]string{"proxy.local"}); err == nil {
+		t.Error("parseTrustedProxies() accepted a host name")
+	}
+}
+
+func TestForgedForwardedForIsLockedOut(t *testing.T) {
+	a, _, _ := newTestAuth(t)
+	handler := a.Wrap(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	// A tunnelled client (ssh -R) arrives from loopback and picks a new
+	// X-Forwarded-For for every attempt
+	code := 0
+	for i := 0; i <= lockoutThreshold; i++ {
+		r := httptest.NewRequest("GET", "/api/repos", nil)
+		r.RemoteAddr = "127.0.0.1:5555"
+		r.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i))
+		r.Header.Set("Authorization", "Bearer wrong")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		code = w.Code
+	}
+	if code != http.StatusTooManyRequests {
+		t.Errorf("status = %d after %d failures, want %d", code, lockoutThreshold+1, http.StatusTooManyRequests)
+	}
+}
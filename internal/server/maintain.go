@@ -0,0 +1,48 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"sync"
+
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/slog"
+)
+
+// maintaining tracks repositories with a maintenance run in progress
+var maintaining sync.Map
+
+// maintainRepo refreshes the commit-graph and, if needed, the bitmap of a
+// repository after a push. Pushes arriving while a run is in progress do not
+// start another one; the next push catches up.
+func maintainRepo(barePath string) {
+	if _, running := maintaining.LoadOrStore(barePath, struct{}{}); running {
+		return
+	}
+	defer maintaining.Delete(barePath)
+
+	if err := git.Maintain(barePath); err != nil {
+		slog.WithComponent("maintenance").Warn("Repository maintenance failed", map[string]interface{}{
+			"repo":  barePath,
+			"error": err.Error(),
+		})
+	}
+}
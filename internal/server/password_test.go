@@ -0,0 +1,83 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashPasswordArgon2id(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword() error: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$") {
+		t.Errorf("hash %q is not in argon2id format", hash)
+	}
+	if IsLegacyHash(hash) {
+		t.Error("argon2id hash reported as legacy")
+	}
+
+	if !ValidatePasswordHash("correct horse", hash) {
+		t.Error("ValidatePasswordHash() rejected the correct password")
+	}
+	if ValidatePasswordHash("wrong horse", hash) {
+		t.Error("ValidatePasswordHash() accepted a wrong password")
+	}
+
+	other, _ := HashPassword("correct horse")
+	if other == hash {
+		t.Error("hashes of the same password should use different salts")
+	}
+}
+
+func TestValidatePasswordHashLegacy(t *testing.T) {
+	salted := "abcd:" + hashWithSalt("secret123", "abcd")
+	if !ValidatePasswordHash("secret123", salted) {
+		t.Error("salted legacy hash not accepted")
+	}
+	if !IsLegacyHash(salted) {
+		t.Error("salted hash should be reported as legacy")
+	}
+
+	simple := sha256Hex("secret123")
+	if !ValidatePasswordHash("secret123", simple) {
+		t.Error("unsalted legacy hash not accepted")
+	}
+	if ValidatePasswordHash("nope", simple) {
+		t.Error("unsalted legacy hash accepted a wrong password")
+	}
+}
+
+func TestValidatePasswordHashMalformed(t *testing.T) {
+	bad := []string{
+		"$argon2id$",
+		"$argon2id$v=19$m=0,t=2,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=18$m=19456,t=2,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=19456,t=2,p=1$!!!$aGFzaA",
+	}
+	for _, h := range bad {
+		if ValidatePasswordHash("anything", h) {
+			t.Errorf("ValidatePasswordHash() accepted malformed hash %q", h)
+		}
+	}
+}
@@ -22,6 +22,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -33,10 +34,15 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/JoeGlenn1213/lgh/internal/certs"
 	"github.com/JoeGlenn1213/lgh/internal/config"
 	"github.com/JoeGlenn1213/lgh/internal/event"
 	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/lfs"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
 	"github.com/JoeGlenn1213/lgh/internal/slog"
+	"github.com/JoeGlenn1213/lgh/internal/tokens"
+	"github.com/JoeGlenn1213/lgh/internal/users"
 	"github.com/JoeGlenn1213/lgh/pkg/ui"
 )
 
@@ -45,6 +51,11 @@ type Server struct {
 	cfg         *config.Config
 	httpServer  *http.Server
 	statusStore *git.StatusStore
+	auth        *AuthMiddleware // nil when authentication is disabled
+	gitLimiter  *git.Limiter    // nil when git processes are not limited
+	gitDaemon   *git.Daemon     // nil when the git:// listener is disabled
+	ssh         *sshServer      // nil when the SSH server is disabled
+	stopTrash   chan struct{}   // nil when trash is never purged automatically
 	onReady     func() // Called after IPC socket is ready, before ListenAndServe
 }
 
@@ -75,26 +86,79 @@ func (s *Server) Start() error {
 	})
 
 	// Create Git backend handler using cfg.ReadOnly
-	gitHandler, err := git.CreateHandler(s.cfg.ReposDir, s.cfg.ReadOnly)
+	gitBackend, err := git.NewBackend(s.cfg.ReposDir, s.cfg.ReadOnly)
 	if err != nil {
 		log.Error("Failed to create git handler", map[string]interface{}{"error": err.Error()})
 		return fmt.Errorf("failed to create git handler: %w", err)
 	}
 
+	// Cap concurrent upload-pack/receive-pack processes (0 disables the limit)
+	if s.cfg.MaxGitProcesses > 0 {
+		s.gitLimiter = git.NewLimiter(s.cfg.MaxGitProcesses, s.cfg.GitQueueSize)
+		gitBackend.SetLimiter(s.gitLimiter)
+	}
+
+	// Repositories created by older versions have no managed hooks yet
+	s.installHooks()
+
+	// LFS objects are served next to the git endpoints
+	lfsHandler := lfs.NewHandler(lfs.NewStore(lfs.GetLFSDir(s.cfg.DataDir)), s.cfg.ReposDir, s.cfg.ReadOnly)
+
 	// Build handler chain
-	var handler = gitHandler
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if lfs.IsLFSRequest(r) {
+			lfsHandler.ServeHTTP(w, r)
+			return
+		}
+		gitBackend.ServeHTTP(w, r)
+	})
+
+	// Pushes to missing repositories may create them (push-to-create rules)
+	reg := registry.New()
+	userStore := users.NewStore()
+	handler = s.pushToCreateMiddleware(reg, userStore, handler)
+
+	// Old URLs of renamed repositories redirect to the new ones
+	handler = s.redirectMiddleware(reg, handler)
 
-	// Add virtual owner middleware (to support /owner/repo.git paths)
+	// Add virtual owner middleware (to support legacy /lgh/repo.git paths)
 	handler = s.virtualOwnerMiddleware(handler)
 
 	// Add logging middleware
 	handler = s.loggingMiddleware(handler)
 
+	// API handler (repositories, commit status)
+	var apiHandler http.Handler = http.HandlerFunc(s.handleAPIRepos)
+
 	// Add authentication middleware if enabled
-	if s.cfg.AuthEnabled && s.cfg.AuthUser != "" && s.cfg.AuthPasswordHash != "" {
+	// Credentials come from config.yaml (single account), users.yaml and tokens.yaml
+	userCount, _ := userStore.Count()
+	tokenStore := tokens.NewStore()
+	tokenCount, _ := tokenStore.Count()
+	hasConfigUser := s.cfg.AuthUser != "" && s.cfg.AuthPasswordHash != ""
+	hasClientCerts := s.cfg.ClientCertAuthEnabled()
+	if s.cfg.AuthEnabled && (hasConfigUser || userCount > 0 || tokenCount > 0 || hasClientCerts) {
 		authMiddleware := NewAuthMiddleware(s.cfg.AuthUser, s.cfg.AuthPasswordHash)
+		authMiddleware.SetUserStore(userStore)
+		authMiddleware.SetTokenStore(tokenStore)
+		authMiddleware.SetRegistry(registry.New())
+		if err := authMiddleware.SetTrustedProxies(s.cfg.TrustedProxies); err != nil {
+			return fmt.Errorf("invalid trusted_proxies: %w", err)
+		}
+		if hasClientCerts {
+			authMiddleware.SetClientCertStore(certs.NewClientStore(config.GetTLSDir()))
+		}
 		handler = authMiddleware.Wrap(handler)
-		ui.Success("Authentication enabled (user: %s)", s.cfg.AuthUser)
+		apiHandler = authMiddleware.Wrap(apiHandler)
+		s.auth = authMiddleware
+		if hasConfigUser {
+			ui.Success("Authentication enabled (user: %s, %d additional accounts, %d tokens)", s.cfg.AuthUser, userCount, tokenCount)
+		} else {
+			ui.Success("Authentication enabled (%d accounts, %d tokens)", userCount, tokenCount)
+		}
+		if hasClientCerts {
+			ui.Success("Client certificate authentication enabled")
+		}
 	}
 
 	// Setup routes
@@ -111,9 +175,12 @@ func (s *Server) Start() error {
 	// Takes a JSON event body and broadcasts it via the Broker.
 	mux.HandleFunc("/debug/events", s.handleDebugEvents)
 
-	// Commit Status API (v1.2.0)
-	// GET/POST /api/repos/{repo}/commits/{sha}/status
-	mux.HandleFunc("/api/repos/", s.handleAPIRepos)
+	// Repository API: GET /api/repos, /api/repos/{repo}, .../branches,
+	// .../tags, .../commits, .../tree/{ref}/{path}, .../raw/{ref}/{path},
+	// .../compare/{base}...{head}, .../archive/{ref}.tar.gz|.zip,
+	// and GET/POST .../commits/{sha}/status (v1.2.0)
+	mux.Handle("/api/repos", apiHandler)
+	mux.Handle("/api/repos/", apiHandler)
 
 	// Git backend for all .git paths
 	mux.Handle("/", handler)
@@ -130,6 +197,15 @@ func (s *Server) Start() error {
 		MaxHeaderBytes:    1 << 20,          // SECURITY: 1MB max header size
 	}
 
+	// Fail early on a broken TLS setup
+	if s.cfg.TLSEnabled() {
+		tlsConfig, err := s.tlsConfig()
+		if err != nil {
+			return err
+		}
+		s.httpServer.TLSConfig = tlsConfig
+	}
+
 	// Check if port is available
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
@@ -154,6 +230,26 @@ func (s *Server) Start() error {
 	// Start IPC Listener (v1.1.0)
 	s.startIPC()
 
+	// Start control socket for local CLI commands (lockouts, ...)
+	s.startControl()
+
+	// Optional read-only git:// listener
+	if s.cfg.GitDaemonPort > 0 {
+		if err := s.startGitDaemon(); err != nil {
+			ui.Warning("git:// listener not started: %v", err)
+		}
+	}
+
+	// Optional SSH transport, authenticated by 'lgh key' keys
+	if s.cfg.SSHPort > 0 {
+		if err := s.startSSH(); err != nil {
+			ui.Warning("SSH server not started: %v", err)
+		}
+	}
+
+	// Purge repositories past trash_retention_days
+	s.startTrashPurge()
+
 	// Fire onReady callback (e.g., auto-start ActionD)
 	if s.onReady != nil {
 		s.onReady()
@@ -161,7 +257,12 @@ func (s *Server) Start() error {
 
 	// Start server
 	log.Info("Server started successfully")
-	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if s.cfg.TLSEnabled() {
+		err = s.httpServer.ListenAndServeTLS(s.cfg.TLSCert, s.cfg.TLSKey)
+	} else {
+		err = s.httpServer.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
 		log.Error("Server error", map[string]interface{}{"error": err.Error()})
 		return fmt.Errorf("server error: %w", err)
 	}
@@ -169,6 +270,39 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// installHooks writes the managed git hooks into every registered repository
+func (s *Server) installHooks() {
+	repos, err := registry.New().List()
+	if err != nil {
+		return
+	}
+	for _, repo := range repos {
+		if err := git.InstallHooks(repo.BarePath); err != nil {
+			ui.Warning("Managed hooks not installed for '%s': %v", repo.Name, err)
+		}
+	}
+}
+
+// tlsConfig builds the TLS configuration, requesting client certificates
+// from the local CA when client certificate authentication is enabled
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	if _, err := tls.LoadX509KeyPair(s.cfg.TLSCert, s.cfg.TLSKey); err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w (run 'lgh tls init')", err)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.cfg.ClientCertAuthEnabled() {
+		ca, err := certs.LoadCA(config.GetTLSDir())
+		if err != nil {
+			return nil, fmt.Errorf("client certificate authentication needs the local CA: %w", err)
+		}
+		// Certificates are optional so that passwords and tokens keep working
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		tlsConfig.ClientCAs = ca.CertPool()
+	}
+	return tlsConfig, nil
+}
+
 // Stop gracefully stops the server
 func (s *Server) Stop() error {
 	slog.Info("Server stopping")
@@ -179,8 +313,19 @@ func (s *Server) Stop() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if s.gitDaemon != nil {
+		_ = s.gitDaemon.Close()
+	}
+	if s.ssh != nil {
+		s.ssh.close()
+	}
+	if s.stopTrash != nil {
+		close(s.stopTrash)
+	}
+
 	// Remove PID file
 	_ = os.Remove(config.GetPIDPath())
+	_ = os.Remove(GetControlSocketPath())
 
 	// Close logger
 	_ = slog.Close()
@@ -219,9 +364,16 @@ func (s *Server) handleShutdown() {
 func (s *Server) displayStartupInfo() {
 	ui.Success("LGH Server started successfully!")
 	fmt.Println()
-	ui.Info("  Address:   http://%s:%d", s.cfg.BindAddress, s.cfg.Port)
+	ui.Info("  Address:   %s", s.cfg.BaseURL(s.cfg.BindAddress))
 	ui.Info("  Repos Dir: %s", s.cfg.ReposDir)
 
+	if s.cfg.GitDaemonPort > 0 {
+		ui.Info("  git://:    git://%s:%d (exported repos, read-only)", s.cfg.BindAddress, s.cfg.GitDaemonPort)
+	}
+	if s.cfg.SSHPort > 0 {
+		ui.Info("  SSH:       ssh://git@%s:%d (keys: lgh key list)", s.cfg.BindAddress, s.cfg.SSHPort)
+	}
+
 	if s.cfg.ReadOnly {
 		ui.Warning("  Mode:      READ-ONLY (push disabled)")
 	} else {
@@ -276,6 +428,11 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer (for Flush)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // IsRunning checks if the server is running by checking PID file
 // Fixed: Uses platform-specific checkProcessRunning to handle PID reuse and existence check
 func IsRunning() (bool, int) {
@@ -303,12 +460,12 @@ func IsRunning() (bool, int) {
 // GetServerURL returns the server URL
 func GetServerURL() string {
 	cfg := config.Get()
-	return fmt.Sprintf("http://%s:%d", cfg.BindAddress, cfg.Port)
+	return cfg.BaseURL(cfg.BindAddress)
 }
 
-// virtualOwnerMiddleware strips the first path segment if it looks like an owner
-// e.g. /owner/repo.git -> /repo.git
-// This allows tools that strictly enforce owner/repo structure to work with flat LGH repos.
+// virtualOwnerMiddleware strips the legacy "lgh" owner of top-level repositories
+// e.g. /lgh/repo.git -> /repo.git
+// Namespaced paths such as /team/repo.git are real repositories and pass through unchanged.
 func (s *Server) virtualOwnerMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
@@ -329,8 +486,8 @@ func (s *Server) virtualOwnerMiddleware(next http.Handler) http.Handler {
 		}
 
 		// If .git is the second segment (index 1), and index 0 is "lgh"
-		// We strict this to "lgh" to avoid security confusion or arbitrary path support.
-		if gitIdx == 1 && parts[0] == "lgh" {
+		// "lgh" is a reserved namespace, so this never hides a real repository.
+		if gitIdx == 1 && parts[0] == registry.LegacyNamespace {
 			// Reconstruct path starting from repo name
 			// /lgh/repo.git/info/refs -> /repo.git/info/refs
 			newPath := "/" + strings.Join(parts[1:], "/")
@@ -384,22 +541,127 @@ func (s *Server) handleDebugEvents(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte(`{"status":"ok"}`))
 }
 
-// handleAPIRepos routes /api/repos/{repo}/commits/{sha}/status requests
+// apiResources are the sub-resources of /api/repos/{repo}/... The repository
+// name is everything before the first of them, so it may include a namespace.
+var apiResources = map[string]bool{
+	"archive":  true,
+	"branches": true,
+	"commits":  true,
+	"compare":  true,
+	"raw":      true,
+	"tags":     true,
+	"tree":     true,
+}
+
+// splitAPIRepoPath splits the path after /api/repos/ into the repository name
+// and the remaining segments: "team/app/commits/abc/status" -> "team/app",
+// ["commits", "abc", "status"]. Names may contain resource words
+// ("team/tree/x"), so the longest split naming a known repository wins;
+// without a match (or without reg) the path is split at the first one.
+func splitAPIRepoPath(reg *registry.Registry, path string) (string, []string) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	name := func(i int) string {
+		return strings.TrimSuffix(strings.Join(parts[:i], "/"), ".git")
+	}
+
+	var splits []int
+	for i := 1; i < len(parts); i++ {
+		if apiResources[parts[i]] {
+			splits = append(splits, i)
+		}
+	}
+	if len(splits) == 0 {
+		return name(len(parts)), nil
+	}
+
+	if reg != nil {
+		known := func(n string) bool {
+			if reg.Exists(n) {
+				return true
+			}
+			_, ok := reg.ResolveRedirect(n)
+			return ok
+		}
+		if known(name(len(parts))) {
+			return name(len(parts)), nil
+		}
+		for k := len(splits) - 1; k > 0; k-- {
+			if known(name(splits[k])) {
+				return name(splits[k]), parts[splits[k]:]
+			}
+		}
+	}
+	return name(splits[0]), parts[splits[0]:]
+}
+
+// handleAPIRepos routes /api/repos and /api/repos/{repo}/... requests
 func (s *Server) handleAPIRepos(w http.ResponseWriter, r *http.Request) {
-	// Parse path: /api/repos/{repo}/commits/{sha}/status
-	path := strings.TrimPrefix(r.URL.Path, "/api/repos/")
-	parts := strings.Split(path, "/")
-	
-	// Expected: {repo}/commits/{sha}/status
-	if len(parts) < 4 || parts[1] != "commits" || parts[3] != "status" {
-		http.Error(w, "invalid path, expected /api/repos/{repo}/commits/{sha}/status", http.StatusBadRequest)
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/repos"), "/")
+	repo, parts := splitAPIRepoPath(registry.New(), rest)
+
+	// Commit statuses: /api/repos/{repo}/commits/{sha}/status
+	if len(parts) == 3 && parts[0] == "commits" && parts[2] == "status" {
+		if registry.ValidateName(repo) != nil {
+			writeAPIError(w, http.StatusBadRequest, "invalid repository name")
+			return
+		}
+		s.handleCommitStatus(w, r, repo, parts[1])
+		return
+	}
+
+	// Repositories are created, changed and deleted; everything else is read-only
+	manage := (rest == "" && r.Method == http.MethodPost) ||
+		(rest != "" && len(parts) == 0 && (r.Method == http.MethodPatch || r.Method == http.MethodDelete))
+	if !manage && !isAPIRead(r) {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if manage && s.auth == nil {
+		// Browsing is fine without authentication, creating and deleting is not
+		writeAPIError(w, http.StatusForbidden, "repository management requires authentication")
 		return
 	}
-	
-	repo := parts[0]
-	sha := parts[2]
-	
-	s.handleCommitStatus(w, r, repo, sha)
+	if rest == "" {
+		if r.Method == http.MethodPost {
+			s.handleAPICreateRepo(w, r)
+		} else {
+			s.handleAPIRepoList(w, r)
+		}
+		return
+	}
+	if registry.ValidateName(repo) != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid repository name")
+		return
+	}
+
+	mapping, ok := s.findAPIRepo(w, r, repo, parts)
+	if !ok {
+		return
+	}
+	switch {
+	case r.Method == http.MethodPatch:
+		s.handleAPIUpdateRepo(w, r, mapping)
+	case r.Method == http.MethodDelete:
+		s.handleAPIDeleteRepo(w, r, mapping)
+	case len(parts) == 0:
+		s.handleAPIRepo(w, r, mapping)
+	case len(parts) == 1 && parts[0] == "branches":
+		s.handleAPIBranches(w, mapping)
+	case len(parts) == 1 && parts[0] == "tags":
+		s.handleAPITags(w, mapping)
+	case len(parts) == 1 && parts[0] == "commits":
+		s.handleAPICommits(w, r, mapping)
+	case parts[0] == "tree":
+		s.handleAPITree(w, r, mapping, parts[1:])
+	case parts[0] == "raw":
+		s.handleAPIRaw(w, r, mapping, parts[1:])
+	case parts[0] == "compare":
+		s.handleAPICompare(w, r, mapping, parts[1:])
+	case parts[0] == "archive":
+		s.handleAPIArchive(w, r, mapping, parts[1:])
+	default:
+		writeAPIError(w, http.StatusNotFound, "unknown API endpoint")
+	}
 }
 
 // handleCommitStatus handles GET/POST for commit status
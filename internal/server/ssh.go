@@ -0,0 +1,408 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/keys"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/users"
+	"github.com/JoeGlenn1213/lgh/pkg/ui"
+)
+
+// hostKeyFile is the SSH host key inside the SSH directory
+const hostKeyFile = "host_ed25519_key"
+
+// Permission extensions set by the public key callback
+const (
+	sshExtUser  = "lgh-user"
+	sshExtOwner = "lgh-owner"
+)
+
+// sshServer serves git over SSH: exec requests for git-upload-pack,
+// git-receive-pack and git-upload-archive, authenticated by the keys managed with 'lgh key'
+type sshServer struct {
+	cfg      *config.Config
+	keys     *keys.Store
+	users    *users.Store
+	registry *registry.Registry
+	limiter  *git.Limiter
+	gitPath  string
+	lghPath  string
+
+	ln     net.Listener
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// startSSH starts the embedded SSH server next to the HTTP server
+func (s *Server) startSSH() error {
+	if s.cfg.SSHPort == s.cfg.Port || s.cfg.SSHPort == s.cfg.GitDaemonPort {
+		return fmt.Errorf("ssh_port %d is already used by another listener", s.cfg.SSHPort)
+	}
+
+	gitPath, err := git.CheckGitInstalled()
+	if err != nil {
+		return err
+	}
+
+	hostKey, err := loadOrCreateHostKey(filepath.Join(config.GetSSHDir(), hostKeyFile))
+	if err != nil {
+		return err
+	}
+
+	lghPath, _ := os.Executable()
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := &sshServer{
+		cfg:      s.cfg,
+		keys:     keys.NewStore(),
+		users:    users.NewStore(),
+		registry: registry.New(),
+		limiter:  s.gitLimiter,
+		gitPath:  gitPath,
+		lghPath:  lghPath,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	sshConfig := &ssh.ServerConfig{
+		PublicKeyCallback: srv.authenticate,
+		ServerVersion:     "SSH-2.0-LGH",
+	}
+	sshConfig.AddHostKey(hostKey)
+
+	addr := net.JoinHostPort(s.cfg.BindAddress, fmt.Sprintf("%d", s.cfg.SSHPort))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	srv.ln = ln
+	s.ssh = srv
+
+	go srv.serve(sshConfig)
+	return nil
+}
+
+// close stops the SSH server and kills running git processes
+func (srv *sshServer) close() {
+	srv.cancel()
+	_ = srv.ln.Close()
+}
+
+// loadOrCreateHostKey reads the host key, generating an ed25519 key on first use
+func loadOrCreateHostKey(path string) (ssh.Signer, error) {
+	// nolint:gosec // G304: path is internally constructed and trusted
+	if data, err := os.ReadFile(path); err == nil {
+		return ssh.ParsePrivateKey(data)
+	}
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate SSH host key: %w", err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "LGH host key")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SSH host key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create SSH directory: %w", err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write SSH host key: %w", err)
+	}
+	return ssh.NewSignerFromKey(priv)
+}
+
+// authenticate accepts keys from the authorized_keys store. Keys bound to a
+// user that no longer exists are refused.
+func (srv *sshServer) authenticate(conn ssh.ConnMetadata, pub ssh.PublicKey) (*ssh.Permissions, error) {
+	key, err := srv.keys.Find(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := key.User == "" || key.User == srv.cfg.AuthUser
+	if !owner {
+		if _, err := srv.users.Find(key.User); err != nil {
+			return nil, fmt.Errorf("user '%s' of key '%s' does not exist", key.User, key.Name)
+		}
+	}
+
+	perms := &ssh.Permissions{Extensions: map[string]string{sshExtUser: key.Identity()}}
+	if owner {
+		perms.Extensions[sshExtOwner] = "true"
+	}
+	return perms, nil
+}
+
+// serve accepts SSH connections until the server is closed
+func (srv *sshServer) serve(sshConfig *ssh.ServerConfig) {
+	for {
+		conn, err := srv.ln.Accept()
+		if err != nil {
+			if srv.ctx.Err() == nil {
+				ui.Error("SSH listener stopped: %v", err)
+			}
+			return
+		}
+		go srv.handleConn(conn, sshConfig)
+	}
+}
+
+// handleConn runs the SSH handshake and serves the session channels
+func (srv *sshServer) handleConn(conn net.Conn, sshConfig *ssh.ServerConfig) {
+	_ = conn.SetDeadline(time.Now().Add(30 * time.Second))
+	sconn, chans, reqs, err := ssh.NewServerConn(conn, sshConfig)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	_ = conn.SetDeadline(time.Time{})
+	defer func() { _ = sconn.Close() }()
+
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only session channels are supported")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go srv.handleSession(sconn, channel, requests)
+	}
+}
+
+// handleSession serves one session: env requests for GIT_PROTOCOL, then a
+// single exec request
+func (srv *sshServer) handleSession(sconn *ssh.ServerConn, channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer func() { _ = channel.Close() }()
+
+	username := sconn.Permissions.Extensions[sshExtUser]
+	protocol := ""
+
+	for req := range requests {
+		switch req.Type {
+		case "env":
+			var kv struct{ Name, Value string }
+			if err := ssh.Unmarshal(req.Payload, &kv); err == nil && kv.Name == "GIT_PROTOCOL" {
+				protocol = git.SanitizeProtocol(kv.Value)
+			}
+			_ = req.Reply(true, nil)
+
+		case "exec":
+			var execReq struct{ Command string }
+			if err := ssh.Unmarshal(req.Payload, &execReq); err != nil {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			_ = req.Reply(true, nil)
+
+			start := time.Now()
+			err := srv.runGit(sconn, channel, execReq.Command, protocol)
+			status := 0
+			if err != nil {
+				status = 1
+				fmt.Fprintf(channel.Stderr(), "ERROR: %v\n", err)
+			}
+			logSSHRequest(execReq.Command, username, sconn.RemoteAddr().String(), err, time.Since(start))
+			sendExitStatus(channel, status)
+			return
+
+		case "shell":
+			_ = req.Reply(true, nil)
+			fmt.Fprintf(channel.Stderr(), "Hi %s! You've successfully authenticated, but LGH does not provide shell access.\n", username)
+			sendExitStatus(channel, 1)
+			return
+
+		default:
+			_ = req.Reply(false, nil)
+		}
+	}
+}
+
+// runGit checks and runs a git command such as "git-upload-pack 'app.git'"
+func (srv *sshServer) runGit(sconn *ssh.ServerConn, channel ssh.Channel, command, protocol string) error {
+	service, repoName, err := parseSSHCommand(command)
+	if err != nil {
+		return err
+	}
+
+	push := service == "git-receive-pack"
+	if push && srv.cfg.ReadOnly {
+		return fmt.Errorf("repository is read-only, push operations are not allowed")
+	}
+
+	username := sconn.Permissions.Extensions[sshExtUser]
+	barePath := registry.BarePathFor(srv.cfg.ReposDir, repoName)
+	movedFrom := ""
+	if !git.IsBareRepo(barePath) {
+		// Old names of renamed repositories keep working while the redirect lasts
+		if newName, ok := srv.registry.ResolveRedirect(repoName); ok {
+			movedFrom, repoName = repoName, newName
+			barePath = registry.BarePathFor(srv.cfg.ReposDir, repoName)
+		}
+	}
+	if push && !git.IsBareRepo(barePath) {
+		if _, err := createOnPush(srv.cfg, srv.registry, srv.users, username, repoName); err != nil {
+			return fmt.Errorf("failed to create repository: %w", err)
+		}
+	}
+	if !git.IsBareRepo(barePath) {
+		return fmt.Errorf("repository not found: %s.git", repoName)
+	}
+
+	if sconn.Permissions.Extensions[sshExtOwner] == "" {
+		required := registry.PermRead
+		if push {
+			required = registry.PermWrite
+		}
+		if _, err := checkRepoAccess(srv.registry, srv.users, username, repoName, required); err != nil {
+			return err
+		}
+	}
+	if movedFrom != "" {
+		fmt.Fprintf(channel.Stderr(), "Repository '%s' has moved to '%s', please update your remote\n", movedFrom, repoName)
+	}
+
+	ctx, cancel := context.WithCancel(srv.ctx)
+	defer cancel()
+
+	if srv.limiter != nil {
+		release, err := srv.limiter.Acquire(ctx)
+		if err != nil {
+			return fmt.Errorf("server busy, try again later")
+		}
+		defer release()
+	}
+
+	env := git.ServiceEnv(username, srv.lghPath, protocol)
+	if push {
+		host, _, _ := net.SplitHostPort(sconn.RemoteAddr().String())
+		env = append(env,
+			git.PushIDEnv+"="+uuid.New().String(),
+			"GIT_COMMITTER_NAME="+username,
+			"GIT_COMMITTER_EMAIL="+username+"@ssh."+host,
+		)
+	}
+
+	// nolint:gosec // G204: service is one of sshServices, barePath is inside ReposDir
+	cmd := exec.CommandContext(ctx, srv.gitPath, strings.TrimPrefix(service, "git-"), barePath)
+	cmd.Env = env
+	cmd.Stdout = channel
+	cmd.Stderr = channel.Stderr()
+
+	// The client may keep the channel open after git exits, so stdin is
+	// copied by hand instead of being waited for
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() {
+		_, _ = io.Copy(stdin, channel)
+		_ = stdin.Close()
+	}()
+
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// git has already explained the failure on stderr
+			return fmt.Errorf("%s exited with status %d", service, exitErr.ExitCode())
+		}
+		return err
+	}
+	return nil
+}
+
+// sshServices are the git commands allowed over SSH; upload-archive
+// serves 'git archive --remote'
+var sshServices = map[string]bool{
+	"git-upload-pack":    true,
+	"git-receive-pack":   true,
+	"git-upload-archive": true,
+}
+
+// parseSSHCommand parses "git-upload-pack '/owner/app.git'" (also
+// "git upload-pack ...") into the service and the repository name
+func parseSSHCommand(command string) (service, repo string, err error) {
+	command = strings.TrimSpace(command)
+	if rest, ok := strings.CutPrefix(command, "git "); ok {
+		command = "git-" + strings.TrimSpace(rest)
+	}
+
+	service, arg, ok := strings.Cut(command, " ")
+	if !ok || !sshServices[service] {
+		return "", "", fmt.Errorf("unsupported command: only git-upload-pack, git-receive-pack and git-upload-archive are allowed")
+	}
+
+	arg = strings.TrimSpace(arg)
+	if len(arg) >= 2 && (arg[0] == '\'' || arg[0] == '"') && arg[len(arg)-1] == arg[0] {
+		arg = arg[1 : len(arg)-1]
+	}
+	arg = strings.TrimSuffix(strings.Trim(arg, "/"), ".git")
+
+	// Top-level repositories may be addressed with the legacy "lgh" owner
+	arg = strings.TrimPrefix(arg, registry.LegacyNamespace+"/")
+
+	if registry.ValidateName(arg) != nil {
+		return "", "", fmt.Errorf("invalid repository path")
+	}
+	return service, arg, nil
+}
+
+// sendExitStatus reports the command exit status to the client
+func sendExitStatus(channel ssh.Channel, status int) {
+	payload := struct{ Status uint32 }{uint32(status)}
+	_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(&payload))
+}
+
+// logSSHRequest prints SSH requests like the HTTP request log
+func logSSHRequest(command, username, remote string, err error, d time.Duration) {
+	status := ui.Green("ok")
+	if err != nil {
+		status = ui.Red(err.Error())
+	}
+	fmt.Printf("%s %s %s %s %s\n", ui.Gray("SSH"), command, status, ui.Gray(d.String()), ui.Gray(username+"@"+remote))
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import "testing"
+
+func TestParseSSHCommand(t *testing.T) {
+	tests := []struct {
+		command, service, repo string
+		ok                     bool
+	}{
+		{"git-upload-pack '/app.git'", "git-upload-pack", "app", true},
+		{"git-receive-pack 'app.git'", "git-receive-pack", "app", true},
+		{"git upload-pack '/team/app.git'", "git-upload-pack", "team/app", true},
+		{"git-upload-pack app", "git-upload-pack", "app", true},
+		{"git-upload-pack '/lgh/app.git'", "git-upload-pack", "app", true},
+		{"git-upload-pack '../etc.git'", "", "", false},
+		{"git-upload-pack '/a//b.git'", "", "", false},
+		{"git-upload-archive 'team/app.git'", "git-upload-archive", "team/app", true},
+		{"git-upload-archive--writer 'app.git'", "", "", false},
+		{"sh -c id", "", "", false},
+		{"git-upload-pack", "", "", false},
+	}
+	for _, tt := range tests {
+		service, repo, err := parseSSHCommand(tt.command)
+		if (err == nil) != tt.ok {
+			t.Errorf("%q: err = %v, want ok=%v", tt.command, err, tt.ok)
+			continue
+		}
+		if service != tt.service || repo != tt.repo {
+			t.Errorf("%q: got (%q, %q), want (%q, %q)", tt.command, service, repo, tt.service, tt.repo)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package server
+
+import (
+	"time"
+
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+	"github.com/JoeGlenn1213/lgh/internal/slog"
+	"github.com/JoeGlenn1213/lgh/internal/trash"
+)
+
+// trashPurgeInterval is how often the server purges expired trash
+const trashPurgeInterval = time.Hour
+
+// startTrashPurge purges repositories past trash_retention_days now and
+// then every trashPurgeInterval until Stop
+func (s *Server) startTrashPurge() {
+	if s.cfg.TrashRetentionDays <= 0 {
+		return
+	}
+
+	s.stopTrash = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(trashPurgeInterval)
+		defer ticker.Stop()
+		for {
+			s.purgeTrash()
+			select {
+			case <-ticker.C:
+			case <-s.stopTrash:
+				return
+			}
+		}
+	}()
+}
+
+func (s *Server) purgeTrash() {
+	purged, err := trash.PurgeExpired(registry.New(), s.cfg)
+	for _, t := range purged {
+		slog.Info("Purged trashed repository", map[string]interface{}{
+			"repo":       t.Name,
+			"deleted_at": t.DeletedAt,
+		})
+	}
+	if err != nil {
+		slog.Error("Trash purge failed", map[string]interface{}{"error": err.Error()})
+	}
+}
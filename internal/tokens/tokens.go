@@ -0,0 +1,360 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package tokens manages scoped personal access tokens
+package tokens
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+// Prefix marks a string as an LGH token, e.g. "lgh_1a2b3c4d_<secret>"
+const Prefix = "lgh_"
+
+// Scope is the access level granted by a token
+type Scope string
+
+const (
+	// ScopeRead allows clone and fetch (git-upload-pack) and the read-only API
+	ScopeRead Scope = "read"
+	// ScopeWrite allows push (git-receive-pack) in addition to read
+	ScopeWrite Scope = "write"
+	// ScopeAdmin allows commit statuses and other /api writes in addition to write
+	ScopeAdmin Scope = "admin"
+)
+
+// level orders scopes so that a higher scope includes the lower ones
+func (s Scope) level() int {
+	switch s {
+	case ScopeRead:
+		return 1
+	case ScopeWrite:
+		return 2
+	case ScopeAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Allows reports whether a token with scope s may perform an operation requiring scope required
+func (s Scope) Allows(required Scope) bool {
+	return s.level() > 0 && s.level() >= required.level()
+}
+
+// ParseScope validates a scope name
+func ParseScope(name string) (Scope, error) {
+	s := Scope(strings.ToLower(name))
+	if s.level() == 0 {
+		return "", fmt.Errorf("invalid scope '%s': must be one of read, write, admin", name)
+	}
+	return s, nil
+}
+
+// Token is a stored access token. Only the SHA256 of the secret is kept on disk.
+type Token struct {
+	ID         string    `yaml:"id"`
+	Name       string    `yaml:"name,omitempty"`
+	User       string    `yaml:"user"`
+	Scope      Scope     `yaml:"scope"`
+	Repo       string    `yaml:"repo,omitempty"`
+	SecretHash string    `yaml:"secret_hash"`
+	CreatedAt  time.Time `yaml:"created_at"`
+	ExpiresAt  time.Time `yaml:"expires_at,omitempty"`
+}
+
+// Expired reports whether the token is past its expiry time
+func (t *Token) Expired() bool {
+	return !t.ExpiresAt.IsZero() && time.Now().After(t.ExpiresAt)
+}
+
+// AllowsRepo reports whether the token may access the given repository.
+// Tokens without a repo restriction apply to all repositories.
+func (t *Token) AllowsRepo(repo string) bool {
+	if t.Repo == "" {
+		return true
+	}
+	return strings.TrimSuffix(repo, ".git") == strings.TrimSuffix(t.Repo, ".git")
+}
+
+// Tokens holds all stored tokens
+type Tokens struct {
+	Tokens []Token `yaml:"tokens"`
+}
+
+// Store manages the tokens file
+type Store struct {
+	path string
+	mu   sync.RWMutex
+}
+
+// NewStore creates a new Store instance
+func NewStore() *Store {
+	return &Store{
+		path: config.GetTokensPath(),
+	}
+}
+
+// NewStoreWithPath creates a Store with a custom path (for testing)
+func NewStoreWithPath(path string) *Store {
+	return &Store{
+		path: path,
+	}
+}
+
+// Format builds the plaintext token handed to the user
+func Format(id, secret string) string {
+	return Prefix + id + "_" + secret
+}
+
+// Parse splits a plaintext token into its ID and secret
+func Parse(raw string) (id, secret string, ok bool) {
+	if !strings.HasPrefix(raw, Prefix) {
+		return "", "", false
+	}
+	id, secret, ok = strings.Cut(strings.TrimPrefix(raw, Prefix), "_")
+	if !ok || id == "" || secret == "" {
+		return "", "", false
+	}
+	return id, secret, true
+}
+
+// HashSecret returns the hex SHA256 of a token secret.
+// Secrets are long random strings, so a plain digest is sufficient.
+func HashSecret(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
+
+// load reads the tokens file with file locking
+func (s *Store) load() (*Tokens, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fileLock := registry.NewFileLock(s.path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fileLock.Unlock()
+
+	tokens := &Tokens{Tokens: []Token{}}
+
+	// nolint:gosec // G304: path is internally constructed and trusted
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tokens, nil
+		}
+		return nil, fmt.Errorf("failed to read tokens file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, tokens); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// save writes the tokens file with file locking
+func (s *Store) save(tokens *Tokens) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fileLock := registry.NewFileLock(s.path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fileLock.Unlock()
+
+	data, err := yaml.Marshal(tokens)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tokens: %w", err)
+	}
+
+	// Ensure directory exists
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// SECURITY: Token hashes are only readable by the owner
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write tokens file: %w", err)
+	}
+
+	return nil
+}
+
+// Add stores a new token
+func (s *Store) Add(token Token) error {
+	if token.ID == "" || token.SecretHash == "" {
+		return fmt.Errorf("token ID and secret hash are required")
+	}
+	if token.Scope.level() == 0 {
+		return fmt.Errorf("invalid scope '%s'", token.Scope)
+	}
+
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tokens.Tokens {
+		if t.ID == token.ID {
+			return fmt.Errorf("token '%s' already exists", token.ID)
+		}
+	}
+
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	tokens.Tokens = append(tokens.Tokens, token)
+
+	return s.save(tokens)
+}
+
+// Revoke removes a token by ID
+func (s *Store) Revoke(id string) error {
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	newTokens := []Token{}
+	for _, t := range tokens.Tokens {
+		if t.ID == id {
+			found = true
+			continue
+		}
+		newTokens = append(newTokens, t)
+	}
+
+	if !found {
+		return fmt.Errorf("token '%s' not found", id)
+	}
+
+	tokens.Tokens = newTokens
+	return s.save(tokens)
+}
+
+// RenameRepo points tokens restricted to a renamed repository to its new name
+func (s *Store) RenameRepo(oldRepo, newRepo string) error {
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for i := range tokens.Tokens {
+		if tokens.Tokens[i].Repo != "" && tokens.Tokens[i].AllowsRepo(oldRepo) {
+			tokens.Tokens[i].Repo = newRepo
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return s.save(tokens)
+}
+
+// List returns all stored tokens
+func (s *Store) List() ([]Token, error) {
+	tokens, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return tokens.Tokens, nil
+}
+
+// Count returns the number of stored tokens
+func (s *Store) Count() (int, error) {
+	tokens, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+	return len(tokens.Tokens), nil
+}
+
+// Verify checks a plaintext token and returns the matching stored token.
+// Unknown, malformed and expired tokens are rejected.
+func (s *Store) Verify(raw string) (*Token, error) {
+	id, secret, ok := Parse(raw)
+	if !ok {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	tokens, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := HashSecret(secret)
+	for _, t := range tokens.Tokens {
+		if t.ID != id {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(t.SecretHash), []byte(hash)) != 1 {
+			return nil, fmt.Errorf("invalid token")
+		}
+		if t.Expired() {
+			return nil, fmt.Errorf("token '%s' has expired", t.ID)
+		}
+		return &t, nil
+	}
+
+	return nil, fmt.Errorf("invalid token")
+}
+
+// ParseExpiry converts an expiry such as "30d", "12h" or "never" into a duration.
+// A zero duration means the token never expires.
+func ParseExpiry(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if s == "" || s == "never" || s == "0" {
+		return 0, nil
+	}
+
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		if _, err := fmt.Sscanf(days, "%d", &n); err != nil || n <= 0 || fmt.Sprint(n) != days {
+			return 0, fmt.Errorf("invalid expiry '%s'", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid expiry '%s': use e.g. 30d, 12h or never", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tokens
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	return NewStoreWithPath(filepath.Join(t.TempDir(), "tokens.yaml"))
+}
+
+// ---- Scope ----
+
+func TestScopeAllows(t *testing.T) {
+	tests := []struct {
+		scope    Scope
+		required Scope
+		want     bool
+	}{
+		{ScopeRead, ScopeRead, true},
+		{ScopeRead, ScopeWrite, false},
+		{ScopeRead, ScopeAdmin, false},
+		{ScopeWrite, ScopeRead, true},
+		{ScopeWrite, ScopeWrite, true},
+		{ScopeWrite, ScopeAdmin, false},
+		{ScopeAdmin, ScopeAdmin, true},
+		{Scope("bogus"), ScopeRead, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.scope.Allows(tt.required); got != tt.want {
+			t.Errorf("%s.Allows(%s) = %v, want %v", tt.scope, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestParseScope(t *testing.T) {
+	if s, err := ParseScope("WRITE"); err != nil || s != ScopeWrite {
+		t.Errorf("ParseScope(WRITE) = %q, %v", s, err)
+	}
+	if _, err := ParseScope("root"); err == nil {
+		t.Error("ParseScope(root) should fail")
+	}
+}
+
+// ---- Format / Parse ----
+
+func TestFormatParse(t *testing.T) {
+	raw := Format("abcd1234", "s3cr3t")
+	id, secret, ok := Parse(raw)
+	if !ok || id != "abcd1234" || secret != "s3cr3t" {
+		t.Errorf("Parse(%q) = %q, %q, %v", raw, id, secret, ok)
+	}
+
+	for _, bad := range []string{"", "password", "lgh_", "lgh_abc", "lgh__secret", "lgh_abc_"} {
+		if _, _, ok := Parse(bad); ok {
+			t.Errorf("Parse(%q) should fail", bad)
+		}
+	}
+}
+
+// ---- ParseExpiry ----
+
+func TestParseExpiry(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"30d", 30 * 24 * time.Hour},
+		{"12h", 12 * time.Hour},
+		{"never", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		got, err := ParseExpiry(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseExpiry(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"-1d", "xd", "1.5d", "soon", "-5h"} {
+		if _, err := ParseExpiry(bad); err == nil {
+			t.Errorf("ParseExpiry(%q) should fail", bad)
+		}
+	}
+}
+
+// ---- Store ----
+
+func TestStoreVerify(t *testing.T) {
+	s := newTestStore(t)
+	err := s.Add(Token{ID: "id1", Scope: ScopeWrite, SecretHash: HashSecret("secret1")})
+	if err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+
+	tok, err := s.Verify(Format("id1", "secret1"))
+	if err != nil {
+		t.Fatalf("Verify() error: %v", err)
+	}
+	if tok.Scope != ScopeWrite {
+		t.Errorf("Scope = %q, want %q", tok.Scope, ScopeWrite)
+	}
+
+	if _, err := s.Verify(Format("id1", "wrong")); err == nil {
+		t.Error("Verify() should fail for wrong secret")
+	}
+	if _, err := s.Verify(Format("nope", "secret1")); err == nil {
+		t.Error("Verify() should fail for unknown ID")
+	}
+}
+
+func TestStoreVerifyExpired(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.Add(Token{
+		ID:         "old",
+		Scope:      ScopeRead,
+		SecretHash: HashSecret("x"),
+		ExpiresAt:  time.Now().Add(-time.Minute),
+	})
+
+	if _, err := s.Verify(Format("old", "x")); err == nil {
+		t.Error("Verify() should fail for expired token")
+	}
+}
+
+func TestStoreRevoke(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.Add(Token{ID: "id1", Scope: ScopeRead, SecretHash: HashSecret("a")})
+
+	if err := s.Add(Token{ID: "id1", Scope: ScopeRead, SecretHash: HashSecret("b")}); err == nil {
+		t.Error("Add() should fail for duplicate ID")
+	}
+
+	if err := s.Revoke("id1"); err != nil {
+		t.Fatalf("Revoke() error: %v", err)
+	}
+	if _, err := s.Verify(Format("id1", "a")); err == nil {
+		t.Error("Verify() should fail after revoke")
+	}
+	if err := s.Revoke("id1"); err == nil {
+		t.Error("Revoke() should fail for unknown token")
+	}
+}
+
+func TestTokenAllowsRepo(t *testing.T) {
+	all := Token{}
+	if !all.AllowsRepo("anything") {
+		t.Error("unrestricted token should allow any repo")
+	}
+
+	one := Token{Repo: "my-app"}
+	if !one.AllowsRepo("my-app") || !one.AllowsRepo("my-app.git") {
+		t.Error("token should allow its own repo")
+	}
+	if one.AllowsRepo("other") {
+		t.Error("token should not allow other repos")
+	}
+}
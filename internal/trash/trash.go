@@ -0,0 +1,195 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package trash keeps removed repositories restorable for a while.
+// A trashed bare repository is moved to <repos_dir>/.trash and its registry
+// entry to the trash list of mappings.yaml; commit statuses and LFS objects
+// stay in place until the repository is purged.
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/lfs"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+// Dir returns the directory holding trashed bare repositories. It lives in
+// the repos directory so that moving a repository there is a rename on the
+// same filesystem; the leading dot keeps it out of valid repository names.
+func Dir(reposDir string) string {
+	return filepath.Join(reposDir, ".trash")
+}
+
+// Move moves the bare repository of repo to the trash and its registry
+// entry to the trash list
+func Move(reg *registry.Registry, cfg *config.Config, repo *registry.RepoMapping) (*registry.TrashedRepo, error) {
+	// SECURITY: Only move bare repositories from inside the configured ReposDir
+	if !registry.IsPathSafe(cfg.ReposDir, repo.BarePath) {
+		return nil, fmt.Errorf("security error: bare repository path '%s' is outside of repos directory '%s'",
+			repo.BarePath, cfg.ReposDir)
+	}
+	if !git.IsBareRepo(repo.BarePath) {
+		return nil, fmt.Errorf("'%s' is not a valid bare repository", repo.BarePath)
+	}
+
+	dir := Dir(cfg.ReposDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	// team/app -> team~app.<nanos>.git: flat, and unique per removal
+	trashPath := filepath.Join(dir, fmt.Sprintf("%s.%d.git",
+		strings.ReplaceAll(repo.Name, "/", "~"), time.Now().UnixNano()))
+	if err := os.Rename(repo.BarePath, trashPath); err != nil {
+		return nil, fmt.Errorf("failed to move bare repository to trash: %w", err)
+	}
+
+	trashed, err := reg.Trash(repo.Name, trashPath)
+	if err != nil {
+		// Put the bare repository back so the registry stays consistent
+		_ = os.Rename(trashPath, repo.BarePath)
+		return nil, fmt.Errorf("failed to update registry: %w", err)
+	}
+	return trashed, nil
+}
+
+// Find returns the most recently trashed repository named name
+func Find(reg *registry.Registry, name string) (*registry.TrashedRepo, error) {
+	list, err := reg.TrashList()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i].Name == name {
+			return &list[i], nil
+		}
+	}
+	return nil, fmt.Errorf("repository '%s' not found in trash", name)
+}
+
+// Restore moves a trashed repository back into the repos directory and
+// registers it as name
+func Restore(reg *registry.Registry, cfg *config.Config, trashed *registry.TrashedRepo, name string) (*registry.RepoMapping, error) {
+	if err := registry.ValidateName(name); err != nil {
+		return nil, err
+	}
+	if reg.Exists(name) {
+		return nil, fmt.Errorf("repository '%s' already exists", name)
+	}
+	// SECURITY: Only move bare repositories from inside the trash directory
+	if !registry.IsPathSafe(Dir(cfg.ReposDir), trashed.TrashPath) {
+		return nil, fmt.Errorf("security error: trash path '%s' is outside of trash directory '%s'",
+			trashed.TrashPath, Dir(cfg.ReposDir))
+	}
+	if !git.IsBareRepo(trashed.TrashPath) {
+		return nil, fmt.Errorf("'%s' is not a valid bare repository", trashed.TrashPath)
+	}
+
+	barePath := registry.BarePathFor(cfg.ReposDir, name)
+	if _, err := os.Stat(barePath); err == nil {
+		return nil, fmt.Errorf("bare repository already exists at %s", barePath)
+	}
+	if err := os.MkdirAll(filepath.Dir(barePath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.Rename(trashed.TrashPath, barePath); err != nil {
+		return nil, fmt.Errorf("failed to restore bare repository: %w", err)
+	}
+
+	repo, err := reg.Untrash(trashed.TrashPath, name, barePath)
+	if err != nil {
+		_ = os.Rename(barePath, trashed.TrashPath)
+		return nil, fmt.Errorf("failed to update registry: %w", err)
+	}
+
+	// Statuses and LFS objects stayed under the old name
+	if name != trashed.Name && !reg.Exists(trashed.Name) {
+		_ = git.NewStatusStore(cfg.DataDir).Rename(trashed.Name, name)
+		_ = lfs.NewStore(lfs.GetLFSDir(cfg.DataDir)).Rename(trashed.Name, name)
+	}
+	return repo, nil
+}
+
+// Purge deletes a trashed repository for good
+func Purge(reg *registry.Registry, cfg *config.Config, trashed *registry.TrashedRepo) error {
+	// SECURITY: Only delete bare repositories inside the trash directory
+	if !registry.IsPathSafe(Dir(cfg.ReposDir), trashed.TrashPath) {
+		return fmt.Errorf("security error: trash path '%s' is outside of trash directory '%s'",
+			trashed.TrashPath, Dir(cfg.ReposDir))
+	}
+	if _, err := os.Stat(trashed.TrashPath); err == nil {
+		// Double-check the path is a git bare repository before deleting
+		if !git.IsBareRepo(trashed.TrashPath) {
+			return fmt.Errorf("'%s' is not a valid bare repository, skipping deletion for safety", trashed.TrashPath)
+		}
+		if err := os.RemoveAll(trashed.TrashPath); err != nil {
+			return fmt.Errorf("failed to delete bare repository: %w", err)
+		}
+	}
+
+	if err := reg.DropTrashed(trashed.TrashPath); err != nil {
+		return err
+	}
+
+	// Keep statuses and LFS objects if the name has been taken again
+	if !reg.Exists(trashed.Name) {
+		_ = git.NewStatusStore(cfg.DataDir).Delete(trashed.Name)
+		_ = lfs.NewStore(lfs.GetLFSDir(cfg.DataDir)).Delete(trashed.Name)
+	}
+	return nil
+}
+
+// PurgeExpired purges the repositories trashed more than
+// cfg.TrashRetentionDays ago and returns them. A retention of 0 keeps
+// trashed repositories until they are purged by hand. An entry that cannot
+// be purged does not stop the others; the errors are returned together.
+func PurgeExpired(reg *registry.Registry, cfg *config.Config) ([]registry.TrashedRepo, error) {
+	if cfg.TrashRetentionDays <= 0 {
+		return nil, nil
+	}
+	list, err := reg.TrashList()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour)
+	var (
+		purged []registry.TrashedRepo
+		errs   []error
+	)
+	for i := range list {
+		if list[i].DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := Purge(reg, cfg, &list[i]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", list[i].Name, err))
+			continue
+		}
+		purged = append(purged, list[i])
+	}
+	return purged, errors.Join(errs...)
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/git"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+func setup(t *testing.T) (*registry.Registry, *config.Config) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := &config.Config{
+		DataDir:            dir,
+		ReposDir:           filepath.Join(dir, "repos"),
+		TrashRetentionDays: 30,
+	}
+	return registry.NewWithPath(filepath.Join(dir, "mappings.yaml")), cfg
+}
+
+func addRepo(t *testing.T, reg *registry.Registry, cfg *config.Config, name string) *registry.RepoMapping {
+	t.Helper()
+	barePath := registry.BarePathFor(cfg.ReposDir, name)
+	if err := git.InitBareRepo(barePath); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	if err := reg.Add(name, "", barePath); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	repo, _ := reg.Find(name)
+	return repo
+}
+
+func TestMoveRestorePurge(t *testing.T) {
+	reg, cfg := setup(t)
+	repo := addRepo(t, reg, cfg, "team/app")
+
+	trashed, err := Move(reg, cfg, repo)
+	if err != nil {
+		t.Fatalf("Move() failed: %v", err)
+	}
+	if _, err := os.Stat(repo.BarePath); !os.IsNotExist(err) {
+		t.Error("bare repository should have left the repos directory")
+	}
+	if !registry.IsPathSafe(Dir(cfg.ReposDir), trashed.TrashPath) || !git.IsBareRepo(trashed.TrashPath) {
+		t.Errorf("trash path %s is not a bare repository in the trash", trashed.TrashPath)
+	}
+
+	found, err := Find(reg, "team/app")
+	if err != nil {
+		t.Fatalf("Find() failed: %v", err)
+	}
+	restored, err := Restore(reg, cfg, found, "team/app")
+	if err != nil {
+		t.Fatalf("Restore() failed: %v", err)
+	}
+	if restored.BarePath != repo.BarePath || !git.IsBareRepo(restored.BarePath) {
+		t.Errorf("Restore() = %+v", restored)
+	}
+
+	// Recent entries survive PurgeExpired, Purge deletes them for good
+	if trashed, err = Move(reg, cfg, restored); err != nil {
+		t.Fatalf("Move() failed: %v", err)
+	}
+	if purged, err := PurgeExpired(reg, cfg); err != nil || len(purged) != 0 {
+		t.Fatalf("PurgeExpired() = %v, %v; want nothing purged", purged, err)
+	}
+	if err := Purge(reg, cfg, trashed); err != nil {
+		t.Fatalf("Purge() failed: %v", err)
+	}
+	if _, err := os.Stat(trashed.TrashPath); !os.IsNotExist(err) {
+		t.Error("purged repository should be deleted")
+	}
+	if list, _ := reg.TrashList(); len(list) != 0 {
+		t.Errorf("TrashList() after Purge() = %+v", list)
+	}
+}
+
+func TestPurgeOutsideTrashRefused(t *testing.T) {
+	reg, cfg := setup(t)
+	repo := addRepo(t, reg, cfg, "app")
+
+	// A tampered entry pointing at a live repository must not be deleted
+	outside := &registry.TrashedRepo{RepoMapping: *repo, TrashPath: repo.BarePath}
+	if err := Purge(reg, cfg, outside); err == nil {
+		t.Error("Purge() outside the trash directory should fail")
+	}
+	if _, err := Restore(reg, cfg, outside, "app2"); err == nil {
+		t.Error("Restore() from outside the trash directory should fail")
+	}
+	if !git.IsBareRepo(repo.BarePath) {
+		t.Error("repository outside the trash was touched")
+	}
+}
+
+func TestPurgeExpiredContinuesAfterFailure(t *testing.T) {
+	reg, cfg := setup(t)
+	var trashed []*registry.TrashedRepo
+	for _, name := range []string{"broken", "app"} {
+		repo := addRepo(t, reg, cfg, name)
+		tr, err := Move(reg, cfg, repo)
+		if err != nil {
+			t.Fatalf("Move() failed: %v", err)
+		}
+		trashed = append(trashed, tr)
+	}
+
+	// Age both entries past the retention period
+	mappingsPath := filepath.Join(cfg.DataDir, "mappings.yaml")
+	data, err := os.ReadFile(mappingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = regexp.MustCompile(`deleted_at: .*`).ReplaceAll(data, []byte("deleted_at: 2000-01-01T00:00:00Z"))
+	if err := os.WriteFile(mappingsPath, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first entry is no longer a bare repository and cannot be purged
+	if err := os.RemoveAll(trashed[0].TrashPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(trashed[0].TrashPath, "data"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	purged, err := PurgeExpired(reg, cfg)
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("PurgeExpired() error = %v, want the failure of 'broken'", err)
+	}
+	if len(purged) != 1 || purged[0].Name != "app" {
+		t.Errorf("PurgeExpired() purged %+v, want app", purged)
+	}
+	if _, err := os.Stat(trashed[1].TrashPath); !os.IsNotExist(err) {
+		t.Error("app should be deleted despite the failing entry before it")
+	}
+}
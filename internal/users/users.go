@@ -0,0 +1,296 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package users manages LGH user accounts stored under the data directory
+package users
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/JoeGlenn1213/lgh/internal/config"
+	"github.com/JoeGlenn1213/lgh/internal/registry"
+)
+
+// validName restricts usernames to characters that are safe in Basic auth,
+// environment variables (REMOTE_USER) and file names.
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,63}$`)
+
+// User represents a single user account
+type User struct {
+	Name         string    `yaml:"name"`
+	PasswordHash string    `yaml:"password_hash"`
+	Groups       []string  `yaml:"groups,omitempty"`
+	CreatedAt    time.Time `yaml:"created_at"`
+}
+
+// Users holds all user accounts
+type Users struct {
+	Users []User `yaml:"users"`
+}
+
+// Store manages the users file
+type Store struct {
+	path string
+	mu   sync.RWMutex
+}
+
+// NewStore creates a new Store instance
+func NewStore() *Store {
+	return &Store{
+		path: config.GetUsersPath(),
+	}
+}
+
+// NewStoreWithPath creates a Store with a custom path (for testing)
+func NewStoreWithPath(path string) *Store {
+	return &Store{
+		path: path,
+	}
+}
+
+// ValidateName checks that a username is acceptable
+func ValidateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("username cannot be empty")
+	}
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid username '%s': use letters, digits, '.', '_' or '-' (max 64 characters)", name)
+	}
+	return nil
+}
+
+// ValidateNewName checks the name of a new account. The name of the
+// config.yaml account (owner) is refused: its SSH keys and client
+// certificates carry owner rights.
+func ValidateNewName(name, owner string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if owner != "" && name == owner {
+		return fmt.Errorf("'%s' is the config.yaml account; choose another name", name)
+	}
+	return nil
+}
+
+// load reads the users file with file locking
+func (s *Store) load() (*Users, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fileLock := registry.NewFileLock(s.path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fileLock.Unlock()
+
+	users := &Users{Users: []User{}}
+
+	// nolint:gosec // G304: path is internally constructed and trusted
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return users, nil
+		}
+		return nil, fmt.Errorf("failed to read users file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, users); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal users: %w", err)
+	}
+
+	return users, nil
+}
+
+// save writes the users file with file locking
+func (s *Store) save(users *Users) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fileLock := registry.NewFileLock(s.path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fileLock.Unlock()
+
+	data, err := yaml.Marshal(users)
+	if err != nil {
+		return fmt.Errorf("failed to marshal users: %w", err)
+	}
+
+	// Ensure directory exists
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// SECURITY: Password hashes are only readable by the owner
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write users file: %w", err)
+	}
+
+	return nil
+}
+
+// Add adds a new user account
+func (s *Store) Add(name, passwordHash string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	users, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	for _, u := range users.Users {
+		if u.Name == name {
+			return fmt.Errorf("user '%s' already exists", name)
+		}
+	}
+
+	users.Users = append(users.Users, User{
+		Name:         name,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now(),
+	})
+
+	return s.save(users)
+}
+
+// Remove removes a user account by name
+func (s *Store) Remove(name string) error {
+	users, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	newUsers := []User{}
+	for _, u := range users.Users {
+		if u.Name == name {
+			found = true
+			continue
+		}
+		newUsers = append(newUsers, u)
+	}
+
+	if !found {
+		return fmt.Errorf("user '%s' not found", name)
+	}
+
+	users.Users = newUsers
+	return s.save(users)
+}
+
+// SetPassword replaces the password hash of an existing user
+func (s *Store) SetPassword(name, passwordHash string) error {
+	users, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	for i := range users.Users {
+		if users.Users[i].Name == name {
+			users.Users[i].PasswordHash = passwordHash
+			return s.save(users)
+		}
+	}
+
+	return fmt.Errorf("user '%s' not found", name)
+}
+
+// SetGroups replaces the group memberships of an existing user
+func (s *Store) SetGroups(name string, groups []string) error {
+	for _, g := range groups {
+		if err := ValidateName(g); err != nil {
+			return fmt.Errorf("invalid group: %w", err)
+		}
+	}
+
+	users, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	for i := range users.Users {
+		if users.Users[i].Name == name {
+			users.Users[i].Groups = groups
+			return s.save(users)
+		}
+	}
+
+	return fmt.Errorf("user '%s' not found", name)
+}
+
+// List returns all user accounts
+func (s *Store) List() ([]User, error) {
+	users, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return users.Users, nil
+}
+
+// Find finds a user account by name
+func (s *Store) Find(name string) (*User, error) {
+	users, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range users.Users {
+		if u.Name == name {
+			return &u, nil
+		}
+	}
+
+	return nil, fmt.Errorf("user '%s' not found", name)
+}
+
+// Count returns the number of user accounts
+func (s *Store) Count() (int, error) {
+	users, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+	return len(users.Users), nil
+}
+
+// contextKey is the type for values stored in a request context
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated username
+func NewContext(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, contextKey{}, name)
+}
+
+// FromContext returns the authenticated username stored in ctx, if any
+func FromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(contextKey{}).(string)
+	return name, ok && name != ""
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2025 JoeGlenn1213
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package users
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	return NewStoreWithPath(filepath.Join(t.TempDir(), "users.yaml"))
+}
+
+// ---- ValidateName ----
+
+func TestValidateName(t *testing.T) {
+	valid := []string{"alice", "bob.smith", "ci_bot", "user-1", "A"}
+	for _, name := range valid {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) = %v, want nil", name, err)
+		}
+	}
+
+	invalid := []string{"", "-alice", ".hidden", "a b", "a:b", "a/b", "ünï"}
+	for _, name := range invalid {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) = nil, want error", name)
+		}
+	}
+}
+
+func TestValidateNewName(t *testing.T) {
+	if err := ValidateNewName("alice", "owner"); err != nil {
+		t.Errorf("ValidateNewName(alice) = %v, want nil", err)
+	}
+	if err := ValidateNewName("owner", "owner"); err == nil {
+		t.Error("ValidateNewName() should refuse the config.yaml account name")
+	}
+	if err := ValidateNewName("a b", ""); err == nil {
+		t.Error("ValidateNewName() should refuse invalid names")
+	}
+}
+
+// ---- Store ----
+
+func TestStoreAddFind(t *testing.T) {
+	s := newTestStore(t)
+
+	if err := s.Add("alice", "hash-a"); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+
+	u, err := s.Find("alice")
+	if err != nil {
+		t.Fatalf("Find() error: %v", err)
+	}
+	if u.PasswordHash != "hash-a" {
+		t.Errorf("PasswordHash = %q, want %q", u.PasswordHash, "hash-a")
+	}
+	if u.CreatedAt.IsZero() {
+		t.Error("CreatedAt should be set")
+	}
+
+	if err := s.Add("alice", "hash-b"); err == nil {
+		t.Error("Add() should fail for duplicate user")
+	}
+	if err := s.Add("bad name", "hash"); err == nil {
+		t.Error("Add() should fail for invalid name")
+	}
+}
+
+func TestStoreFindMissing(t *testing.T) {
+	s := newTestStore(t)
+	if _, err := s.Find("nobody"); err == nil {
+		t.Error("Find() should fail for unknown user")
+	}
+}
+
+func TestStoreRemove(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.Add("alice", "h1")
+	_ = s.Add("bob", "h2")
+
+	if err := s.Remove("alice"); err != nil {
+		t.Fatalf("Remove() error: %v", err)
+	}
+	if err := s.Remove("alice"); err == nil {
+		t.Error("Remove() should fail for already removed user")
+	}
+
+	n, err := s.Count()
+	if err != nil {
+		t.Fatalf("Count() error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Count() = %d, want 1", n)
+	}
+}
+
+func TestStoreSetPassword(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.Add("alice", "old")
+
+	if err := s.SetPassword("alice", "new"); err != nil {
+		t.Fatalf("SetPassword() error: %v", err)
+	}
+	u, _ := s.Find("alice")
+	if u.PasswordHash != "new" {
+		t.Errorf("PasswordHash = %q, want %q", u.PasswordHash, "new")
+	}
+
+	if err := s.SetPassword("nobody", "x"); err == nil {
+		t.Error("SetPassword() should fail for unknown user")
+	}
+}
+
+func TestStoreListEmpty(t *testing.T) {
+	s := newTestStore(t)
+	list, err := s.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("List() returned %d users, want 0", len(list))
+	}
+}
+
+// ---- Context ----
+
+func TestContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("FromContext() on empty context should return false")
+	}
+
+	ctx := NewContext(context.Background(), "alice")
+	name, ok := FromContext(ctx)
+	if !ok || name != "alice" {
+		t.Errorf("FromContext() = %q, %v; want %q, true", name, ok, "alice")
+	}
+}
+
+func TestStoreSetGroups(t *testing.T) {
+	s := newTestStore(t)
+	_ = s.Add("alice", "h")
+
+	if err := s.SetGroups("alice", []string{"devs", "ops"}); err != nil {
+		t.Fatalf("SetGroups() error: %v", err)
+	}
+	u, _ := s.Find("alice")
+	if len(u.Groups) != 2 || u.Groups[0] != "devs" {
+		t.Errorf("Groups = %v, want [devs ops]", u.Groups)
+	}
+
+	if err := s.SetGroups("alice", []string{"bad group"}); err == nil {
+		t.Error("SetGroups() should fail for invalid group name")
+	}
+	if err := s.SetGroups("nobody", nil); err == nil {
+		t.Error("SetGroups() should fail for unknown user")
+	}
+}